@@ -0,0 +1,211 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// converterType returns the type a converter is registered against, SrcType and DstType
+// being either sample values or reflect.Type values.
+func converterType(t interface{}) reflect.Type {
+	if rt, ok := t.(reflect.Type); ok {
+		return rt
+	}
+	return reflect.TypeOf(t)
+}
+
+// BidirectionalConverter converts values of TypeA into values of TypeB with Forward,
+// and values of TypeB into values of TypeA with Backward.
+// TypeA and TypeB are sample values or reflect.Type values, like the types of a TypeConverter.
+type BidirectionalConverter struct {
+	TypeA    interface{}
+	TypeB    interface{}
+	Forward  func(a interface{}) (b interface{}, err error)
+	Backward func(b interface{}) (a interface{}, err error)
+}
+
+// converters returns the converters of both directions.
+func (cnv BidirectionalConverter) converters() []TypeConverter {
+	return []TypeConverter{
+		{SrcType: cnv.TypeA, DstType: cnv.TypeB, Fn: cnv.Forward},
+		{SrcType: cnv.TypeB, DstType: cnv.TypeA, Fn: cnv.Backward},
+	}
+}
+
+// converters indexes the converters of the option, which take precedence over the bidirectional ones,
+// themselves taking precedence over the registered ones.
+func (opt Option) converters() *converterSet {
+	converters := registeredConverters()
+	if len(opt.BidirectionalConverters) > 0 {
+		var bidirectional []TypeConverter
+		for _, cnv := range opt.BidirectionalConverters {
+			bidirectional = append(bidirectional, cnv.converters()...)
+		}
+		converters = newConverterSet(bidirectional, converters)
+	}
+	return newConverterSet(opt.Converters, converters)
+}
+
+// validateConverters checks that every converter can be indexed and called.
+func validateConverters(converters []TypeConverter) error {
+	for i, cnv := range converters {
+		if err := validateConverter(i, cnv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateConverter(i int, cnv TypeConverter) error {
+	if (cnv.SrcType == nil) == (cnv.SrcKind == reflect.Invalid) || (cnv.DstType == nil) == (cnv.DstKind == reflect.Invalid) {
+		return fmt.Errorf("%w: converter %d must have either SrcType or SrcKind, and either DstType or DstKind", ErrInvalidConverter, i)
+	}
+	if cnv.Fn == nil && cnv.FnContext == nil {
+		return fmt.Errorf("%w: converter %d has neither Fn nor FnContext", ErrInvalidConverter, i)
+	}
+	if dstType := converterType(cnv.DstType); cnv.resultType != nil && dstType != nil &&
+		!cnv.resultType.AssignableTo(dstType) && !cnv.resultType.ConvertibleTo(dstType) {
+		return fmt.Errorf("%w: converter %d returns %v, which cannot be set to %v", ErrInvalidConverter, i, cnv.resultType, dstType)
+	}
+	return nil
+}
+
+// converterRule is a converter matched by assignability or kind.
+type converterRule struct {
+	cnv              TypeConverter
+	srcType, dstType reflect.Type
+	srcKind, dstKind reflect.Kind
+}
+
+// match returns how closely the rule matches a type pair, lower ranks taking precedence:
+// exact source types first, then source interfaces, then source kinds, and for each of them
+// exact destination types before destination kinds.
+func (r *converterRule) match(from, to reflect.Type) (rank int, ok bool) {
+	switch {
+	case r.srcType == from:
+		rank = 0
+	case r.srcType != nil && r.srcType.Kind() == reflect.Interface && from.Implements(r.srcType):
+		rank = 2
+	case r.srcType == nil && r.srcKind == from.Kind():
+		rank = 4
+	default:
+		return 0, false
+	}
+
+	switch {
+	case r.dstType == to:
+	case r.dstType == nil && r.dstKind == to.Kind():
+		rank++
+	default:
+		return 0, false
+	}
+	return rank, true
+}
+
+// converterSet indexes converters by exact type pair, falling back to the converters
+// registered against interfaces or kinds.
+type converterSet struct {
+	// converters looked up when none of this set matches, nil if there are none
+	fallback *converterSet
+
+	exact map[converterPair]TypeConverter
+	// converters registered against an interface or a kind, in registration order
+	rules []converterRule
+	// resolved fallbacks by type pair, nil if there is none
+	resolved sync.Map
+}
+
+func newConverterSet(converters []TypeConverter, fallback *converterSet) *converterSet {
+	c := &converterSet{fallback: fallback, exact: map[converterPair]TypeConverter{}}
+	for _, cnv := range converters {
+		r := converterRule{
+			cnv:     cnv,
+			srcType: converterType(cnv.SrcType),
+			dstType: converterType(cnv.DstType),
+			srcKind: cnv.SrcKind,
+			dstKind: cnv.DstKind,
+		}
+
+		if r.srcType != nil && r.dstType != nil {
+			// a converter replaces the previous ones of the same pair
+			c.exact[converterPair{SrcType: r.srcType, DstType: r.dstType}] = cnv
+			if r.srcType.Kind() != reflect.Interface {
+				continue
+			}
+		}
+		c.rules = append(c.rules, r)
+	}
+	return c
+}
+
+// has reports whether the set has a converter from the from type to the to type.
+func (c *converterSet) has(from, to reflect.Type) bool {
+	_, ok := c.lookup(from, to)
+	return ok
+}
+
+// lookup returns the converter from the from type to the to type, if any.
+// Every converter of the set takes precedence over the ones of its fallback.
+func (c *converterSet) lookup(from, to reflect.Type) (TypeConverter, bool) {
+	if cnv, ok := c.lookupOwn(from, to); ok || c.fallback == nil {
+		return cnv, ok
+	}
+	return c.fallback.lookup(from, to)
+}
+
+func (c *converterSet) lookupOwn(from, to reflect.Type) (TypeConverter, bool) {
+	pair := converterPair{SrcType: from, DstType: to}
+	if cnv, ok := c.exact[pair]; ok {
+		return cnv, true
+	}
+	if len(c.rules) == 0 {
+		return TypeConverter{}, false
+	}
+
+	if r, ok := c.resolved.Load(pair); ok {
+		if rule := r.(*converterRule); rule != nil {
+			return rule.cnv, true
+		}
+		return TypeConverter{}, false
+	}
+
+	var best *converterRule
+	bestRank := 0
+	for i := range c.rules {
+		// ties are won by the first registered converter
+		if rank, ok := c.rules[i].match(from, to); ok && (best == nil || rank < bestRank) {
+			best, bestRank = &c.rules[i], rank
+		}
+	}
+	c.resolved.Store(pair, best)
+
+	if best == nil {
+		return TypeConverter{}, false
+	}
+	return best.cnv, true
+}
+
+// setConverted sets to to the result of a converter, converting it to the type of to when needed.
+func setConverted(to reflect.Value, result interface{}, s *state) error {
+	if result == nil {
+		// in case we've got a nil value to copy
+		to.Set(reflect.Zero(to.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(result)
+	switch {
+	case v.Type().AssignableTo(to.Type()):
+		to.Set(v)
+	case s.convertible(v.Type(), to.Type()):
+		converted, err := convertValue(v, to.Type(), s)
+		if err != nil {
+			return err
+		}
+		to.Set(converted)
+	default:
+		return fmt.Errorf("%w: converter returned %v, which cannot be set to %v", ErrInvalidConverter, v.Type(), to.Type())
+	}
+	return nil
+}
@@ -1,9 +1,12 @@
 package copier
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"strings"
 	"sync"
@@ -22,9 +25,6 @@ const (
 	// Ignore a destination field from being copied to.
 	tagIgnore
 
-	// Denotes that the value as been copied
-	hasCopied
-
 	// Some default converter types for a nicer syntax
 	String  string  = ""
 	Bool    bool    = false
@@ -36,6 +36,8 @@ const (
 // Option sets copy options
 type Option struct {
 	Converters []TypeConverter
+	// Converters registering a TypeConverter in each direction, used after the Converters above.
+	BidirectionalConverters []BidirectionalConverter
 	// Custom field name mappings to copy values with different names in `fromValue` and `toValue` types.
 	// Examples can be found in `copier_field_name_mapping_test.go`.
 	FieldNameMapping []FieldNameMapping
@@ -45,28 +47,237 @@ type Option struct {
 	IgnoreEmpty   bool
 	CaseSensitive bool
 	DeepCopy      bool
+
+	// ErrorMode tells what to do when copying a value fails, see ErrorMode.
+	ErrorMode ErrorMode
+
+	// With DeepCopy, a source pointer met several times is copied once and the destination shares the copy,
+	// reproducing cycles. Setting ErrorOnCycle returns ErrCycleDetected when a cycle is met instead.
+	ErrorOnCycle bool
+
+	// MaxDepth limits how deep copies recurse into nested structs, slices and maps: recursing into a value
+	// whose path from the root is longer than MaxDepth fails with ErrMaxDepthExceeded.
+	// For instance `Orders[3].Items` has a depth of 3. Zero means no limit.
+	MaxDepth int
+
+	// When copying between structs and maps, nested structs are copied from and into nested maps by default.
+	// Setting a separator such as "." flattens them instead, using keys like "Address.City".
+	MapKeySeparator string
+
+	// FieldTransformers replace the values copied into some fields of the destination structs,
+	// and take precedence over Converters.
+	FieldTransformers []FieldTransformer
+
+	// StrictNumeric makes numeric conversions fail with ErrLossyConversion when the value overflows
+	// the destination type, loses its sign, its fractional part or its precision, instead of being corrupted.
+	StrictNumeric bool
+
+	// StringConversion parses strings copied into numbers and bools, and formats numbers and bools
+	// copied into strings, with the strconv functions. The parse errors are returned.
+	StringConversion bool
+
+	// Integers are not converted into strings by default, as Go would make runes of them, e.g. 65 into "A".
+	// Setting RuneConversion restores this conversion, while StringConversion formats them as decimals instead.
+	RuneConversion bool
+
+	// PointerPolicy tells how pointers copied into pointers of the destination are handled, see PointerPolicy.
+	PointerPolicy PointerPolicy
+
+	// Merge merges the source into the destination rather than replacing its values: the maps keep
+	// the entries absent from the source and the nested structs and maps are merged recursively.
+	// Combined with IgnoreEmpty, it layers the set fields of the source over the destination.
+	Merge bool
+	// SliceMode tells how source slices are copied into existing destination slices, see SliceMode.
+	SliceMode SliceMode
+
+	// SliceKeys match the elements of slices by an identity field rather than by index, like the
+	// `copier:"key=ID"` tag of a slice field, see SliceKey.
+	SliceKeys []SliceKey
+
+	// SkipHooks disables the BeforeCopy and AfterCopy methods of the destination structs.
+	SkipHooks bool
 }
 
-func (opt Option) converters() map[converterPair]TypeConverter {
-	var converters = map[converterPair]TypeConverter{}
+// ErrorMode defines how errors met while copying are reported.
+type ErrorMode uint8
 
-	// save converters into map for faster lookup
-	for i := range opt.Converters {
-		pair := converterPair{
-			SrcType: reflect.TypeOf(opt.Converters[i].SrcType),
-			DstType: reflect.TypeOf(opt.Converters[i].DstType),
+const (
+	// ErrorModeFailFast stops on the first error.
+	ErrorModeFailFast ErrorMode = iota
+	// ErrorModeCollectAll copies everything it can and returns every error met, joined with errors.Join.
+	// The values which failed to be copied are skipped.
+	ErrorModeCollectAll
+	// ErrorModeLenient stops on the first field error, but ignores the errors of slice elements,
+	// of values returned by methods and of converters between whole structs, leaving them unset.
+	// This was the behavior of earlier versions.
+	ErrorModeLenient
+)
+
+// PointerPolicy defines how a source pointer is copied into a destination pointer, such as a struct field.
+// Slices and maps holding pointers are still shared without DeepCopy.
+type PointerPolicy uint8
+
+const (
+	// PointerDefault sets the destination to nil when the source is nil, otherwise copies the pointed value
+	// into the value the destination points to, allocating it if the destination is nil.
+	PointerDefault PointerPolicy = iota
+	// PointerShare makes the destination point to the same value as the source when their types are assignable.
+	// It cannot be used with DeepCopy.
+	PointerShare
+	// PointerClone copies the pointed value into a newly allocated value, leaving the value the destination
+	// pointed to untouched, as it may be shared.
+	PointerClone
+	// PointerSkipNil leaves the destination untouched when the source is nil, e.g. for PATCH updates,
+	// and otherwise behaves like PointerDefault.
+	PointerSkipNil
+)
+
+// validate checks that every converter and field name mapping can be indexed.
+func (opt Option) validate() error {
+	if err := validateConverters(opt.Converters); err != nil {
+		return err
+	}
+	for i, cnv := range opt.BidirectionalConverters {
+		if err := validateBidirectionalConverter(i, cnv); err != nil {
+			return err
+		}
+	}
+	for i, mapping := range opt.FieldNameMapping {
+		if err := validateFieldNameMapping(i, mapping); err != nil {
+			return err
+		}
+	}
+	for i, t := range opt.FieldTransformers {
+		if err := validateFieldTransformer(i, t); err != nil {
+			return err
+		}
+	}
+	for i, key := range opt.SliceKeys {
+		if err := validateSliceKey(i, key); err != nil {
+			return err
+		}
+	}
+	return opt.validateModes()
+}
+
+// withoutInvalidEntries returns opt without its invalid converters and field name mappings of the shapes
+// earlier versions accepted, which Copy, CopyWithOption and CopyContext ignore rather than fail on, as they
+// always did. The invalid entries using the fields added since are reported like New does.
+func (opt Option) withoutInvalidEntries() (Option, error) {
+	var err error
+	opt.Converters, err = withoutInvalid(opt.Converters, validateConverter, func(cnv TypeConverter) bool {
+		return cnv.SrcKind == reflect.Invalid && cnv.DstKind == reflect.Invalid && cnv.FnContext == nil && cnv.resultType == nil
+	})
+	if err != nil {
+		return opt, err
+	}
+	opt.FieldNameMapping, err = withoutInvalid(opt.FieldNameMapping, validateFieldNameMapping, func(mapping FieldNameMapping) bool {
+		return !mapping.Bidirectional
+	})
+	return opt, err
+}
+
+// withoutInvalid returns the entries without the invalid ones for which legacy is true,
+// or the error of the first invalid entry for which it is false.
+func withoutInvalid[T any](entries []T, validate func(int, T) error, legacy func(T) bool) ([]T, error) {
+	var valid []T
+	for i, e := range entries {
+		if err := validate(i, e); err == nil {
+			valid = append(valid, e)
+		} else if !legacy(e) {
+			return nil, err
 		}
+	}
+	if len(valid) == len(entries) {
+		return entries, nil
+	}
+	return valid, nil
+}
+
+func validateBidirectionalConverter(i int, cnv BidirectionalConverter) error {
+	if cnv.TypeA == nil || cnv.TypeB == nil || cnv.Forward == nil || cnv.Backward == nil {
+		return fmt.Errorf("%w: bidirectional converter %d must have TypeA, TypeB, Forward and Backward", ErrInvalidConverter, i)
+	}
+	return nil
+}
+
+func validateFieldNameMapping(i int, mapping FieldNameMapping) error {
+	if mapping.SrcType == nil || mapping.DstType == nil {
+		return fmt.Errorf("%w: mapping %d must have both SrcType and DstType", ErrInvalidFieldNameMapping, i)
+	}
+	if mapping.Bidirectional {
+		names := make(map[string]string, len(mapping.Mapping))
+		for from, to := range mapping.Mapping {
+			if other, ok := names[to]; ok {
+				return fmt.Errorf("%w: mapping %d cannot be inverted, both %s and %s map to %s", ErrInvalidFieldNameMapping, i, other, from, to)
+			}
+			names[to] = from
+		}
+	}
+	return nil
+}
 
-		converters[pair] = opt.Converters[i]
+func validateFieldTransformer(i int, t FieldTransformer) error {
+	if t.DstType == nil || t.Path == "" || t.Fn == nil {
+		return fmt.Errorf("%w: field transformer %d must have DstType, Path and Fn", ErrInvalidFieldTransformer, i)
 	}
+	return nil
+}
 
-	return converters
+func validateSliceKey(i int, key SliceKey) error {
+	if key.Type == nil || key.Field == "" {
+		return fmt.Errorf("%w: slice key %d must have Type and Field", ErrInvalidOption, i)
+	}
+	return nil
+}
+
+// validateModes checks the options taking one of a set of values.
+func (opt Option) validateModes() error {
+	if opt.ErrorMode > ErrorModeLenient {
+		return fmt.Errorf("%w: unknown error mode %d", ErrInvalidOption, opt.ErrorMode)
+	}
+	if opt.PointerPolicy > PointerSkipNil {
+		return fmt.Errorf("%w: unknown pointer policy %d", ErrInvalidOption, opt.PointerPolicy)
+	}
+	if opt.PointerPolicy == PointerShare && opt.DeepCopy {
+		return fmt.Errorf("%w: pointers cannot be shared with DeepCopy", ErrInvalidOption)
+	}
+	if opt.SliceMode > SliceMergeIndex {
+		return fmt.Errorf("%w: unknown slice mode %d", ErrInvalidOption, opt.SliceMode)
+	}
+	if opt.MaxDepth < 0 {
+		return fmt.Errorf("%w: negative max depth %d", ErrInvalidOption, opt.MaxDepth)
+	}
+	return nil
 }
 
+// TypeConverter converts values of SrcType into values of DstType.
+// SrcType and DstType are sample values such as copier.String, or reflect.Type values, which allows
+// registering a converter from an interface type: it is used for every source type implementing it.
+// SrcKind and DstKind can be set instead to match every type of a kind, such as reflect.Int.
+//
+// When several converters match a pair of types, the exact source types win over source interfaces,
+// themselves winning over source kinds, then exact destination types win over destination kinds.
+// Remaining ties are won by the first converter given, except between converters with the same SrcType
+// and DstType: the last one given replaces the others.
 type TypeConverter struct {
 	SrcType interface{}
 	DstType interface{}
+	SrcKind reflect.Kind
+	DstKind reflect.Kind
 	Fn      func(src interface{}) (dst interface{}, err error)
+	// FnContext is used instead of Fn when set, and receives the context given to CopyContext.
+	FnContext func(ctx context.Context, src interface{}) (dst interface{}, err error)
+
+	// type of the values returned by Fn, when known
+	resultType reflect.Type
+}
+
+func (cnv TypeConverter) call(ctx context.Context, src interface{}) (interface{}, error) {
+	if cnv.FnContext != nil {
+		return cnv.FnContext(ctx, src)
+	}
+	return cnv.Fn(src)
 }
 
 type converterPair struct {
@@ -83,7 +294,26 @@ func (opt Option) fieldNameMapping() map[converterPair]FieldNameMapping {
 			DstType: reflect.TypeOf(opt.FieldNameMapping[i].DstType),
 		}
 
-		mapping[pair] = opt.FieldNameMapping[i]
+		mapping[pair] = FieldNameMapping{
+			SrcType: opt.FieldNameMapping[i].SrcType,
+			DstType: opt.FieldNameMapping[i].DstType,
+			// copy the mapping so later changes made by the caller do not invalidate cached plans
+			Mapping: maps.Clone(opt.FieldNameMapping[i].Mapping),
+		}
+	}
+
+	// the explicit mappings take precedence over the inverse ones
+	for _, m := range opt.FieldNameMapping {
+		pair := converterPair{SrcType: reflect.TypeOf(m.DstType), DstType: reflect.TypeOf(m.SrcType)}
+		if _, ok := mapping[pair]; ok || !m.Bidirectional {
+			continue
+		}
+
+		inverse := make(map[string]string, len(m.Mapping))
+		for from, to := range m.Mapping {
+			inverse[to] = from
+		}
+		mapping[pair] = FieldNameMapping{SrcType: m.DstType, DstType: m.SrcType, Mapping: inverse}
 	}
 
 	return mapping
@@ -93,6 +323,9 @@ type FieldNameMapping struct {
 	SrcType interface{}
 	DstType interface{}
 	Mapping map[string]string
+	// Bidirectional also maps the fields of DstType to the fields of SrcType, with the inverse mapping,
+	// unless a mapping from DstType to SrcType is given too.
+	Bidirectional bool
 }
 
 // Valuer lets custom types implement a function returning the actual value to copy.
@@ -117,17 +350,139 @@ type tagNameMapping struct {
 	TagToFieldName map[string]string
 }
 
+// config holds the options of a copy along with their indexed lookups.
+type config struct {
+	Option
+	converters   *converterSet
+	mappings     map[converterPair]FieldNameMapping
+	mappingKeys  map[converterPair]string
+	transformers map[transformerKey]FieldTransformer
+	sliceKeys    map[reflect.Type]SliceKey
+	plans        *planCache
+}
+
+func newConfig(opt Option, plans *planCache) (*config, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := &config{
+		Option:       opt,
+		converters:   opt.converters(),
+		mappings:     opt.fieldNameMapping(),
+		mappingKeys:  map[converterPair]string{},
+		transformers: opt.fieldTransformers(),
+		sliceKeys:    opt.sliceKeys(),
+		plans:        plans,
+	}
+	for pair, mapping := range cfg.mappings {
+		cfg.mappingKeys[pair] = mappingKey(mapping.Mapping)
+	}
+	return cfg, nil
+}
+
+// plan returns the copy plan between two struct types.
+func (cfg *config) plan(fromType, toType reflect.Type) (*structPlan, error) {
+	pair := converterPair{SrcType: fromType, DstType: toType}
+	key := planKey{from: fromType, to: toType, caseSensitive: cfg.CaseSensitive, mapping: cfg.mappingKeys[pair]}
+	return cfg.plans.get(key, getFieldNamesMapping(cfg.mappings, fromType, toType))
+}
+
+// state holds what is specific to a single top-level copy.
+type state struct {
+	*config
+	ctx  context.Context
+	done <-chan struct{}
+	// paths from the root of the copy to the value being copied
+	srcPath []pathElem
+	dstPath []pathElem
+	// destination structs being copied field by field, tracked when there are field transformers
+	structs []structFrame
+	// errors collected with ErrorModeCollectAll
+	errs []error
+	// source pointers already deep copied
+	visited map[visitKey]*visit
+}
+
+func newState(ctx context.Context, cfg *config) *state {
+	return &state{config: cfg, ctx: ctx, done: ctx.Done()}
+}
+
+// run copies fromValue into toValue and returns the errors collected along the way.
+func (s *state) run(toValue, fromValue interface{}) error {
+	err := copier(toValue, fromValue, s)
+	if len(s.errs) > 0 {
+		if err != nil {
+			s.errs = append(s.errs, err)
+		}
+		return errors.Join(s.errs...)
+	}
+	return err
+}
+
+// handle returns err if the copy must stop, or collects it and returns nil to carry on.
+func (s *state) handle(err error) error {
+	if err != nil && s.ErrorMode == ErrorModeCollectAll {
+		s.errs = append(s.errs, err)
+		return nil
+	}
+	return err
+}
+
+// handleLenient is handle for the errors ignored by ErrorModeLenient.
+func (s *state) handleLenient(err error) error {
+	if s.ErrorMode == ErrorModeLenient {
+		return nil
+	}
+	return s.handle(err)
+}
+
+// checkDepth checks that the value being copied is not deeper than MaxDepth.
+func (s *state) checkDepth() error {
+	if s.MaxDepth > 0 && len(s.dstPath) > s.MaxDepth {
+		return fmt.Errorf("%w: %d", ErrMaxDepthExceeded, s.MaxDepth)
+	}
+	return nil
+}
+
+// canceled reports whether the context of the copy is done.
+func (s *state) canceled() bool {
+	if s.done == nil {
+		return false
+	}
+
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
 // Copy copy things
 func Copy(toValue interface{}, fromValue interface{}) (err error) {
-	return copier(toValue, fromValue, Option{})
+	return CopyWithOption(toValue, fromValue, Option{})
 }
 
 // CopyWithOption copy with option
 func CopyWithOption(toValue interface{}, fromValue interface{}, opt Option) (err error) {
-	return copier(toValue, fromValue, opt)
+	return CopyContext(context.Background(), toValue, fromValue, opt)
+}
+
+// CopyContext copy with option, stopping between slice elements and map entries once ctx is done.
+// ctx is also passed to the converters having a FnContext.
+func CopyContext(ctx context.Context, toValue interface{}, fromValue interface{}, opt Option) (err error) {
+	if opt, err = opt.withoutInvalidEntries(); err != nil {
+		return err
+	}
+	cfg, err := newConfig(opt, defaultPlans)
+	if err != nil {
+		return err
+	}
+	return newState(ctx, cfg).run(toValue, fromValue)
 }
 
-func copier(toValue interface{}, fromValue interface{}, opt Option) (err error) {
+func copier(toValue interface{}, fromValue interface{}, s *state) (err error) {
 	if fromCopyValuer, ok := fromValue.(Valuer); ok {
 		fromValue = fromCopyValuer.CopyValue()
 	}
@@ -137,8 +492,8 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		amount     = 1
 		from       = indirect(reflect.ValueOf(fromValue))
 		to         = indirect(reflect.ValueOf(toValue))
-		converters = opt.converters()
-		mappings   = opt.fieldNameMapping()
+		opt        = s.Option
+		converters = s.converters
 	)
 
 	if !to.CanAddr() {
@@ -150,6 +505,22 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		return ErrInvalidCopyFrom
 	}
 
+	defer s.reset(s.mark())
+
+	if err = s.checkDepth(); err != nil {
+		return s.wrap(err, from.Type(), to.Type())
+	}
+
+	if s.DeepCopy {
+		v, skip, err := enterVisited(toValue, fromValue, s)
+		if err != nil || skip {
+			return s.wrap(err, from.Type(), to.Type())
+		}
+		if v != nil {
+			defer func() { v.active-- }()
+		}
+	}
+
 	fromType, isPtrFrom := indirectType(from.Type())
 	toType, _ := indirectType(to.Type())
 
@@ -158,6 +529,10 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 	}
 
 	if toType.Kind() == reflect.Interface {
+		if to.Kind() == reflect.Interface && to.IsNil() {
+			// no type to copy into
+			return ErrInvalidCopyDestination
+		}
 		toType, _ = indirectType(reflect.TypeOf(to.Interface()))
 		oldTo := to
 		to = reflect.New(reflect.TypeOf(to.Interface())).Elem()
@@ -166,10 +541,21 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 		}()
 	}
 
+	if s.StringConversion && isStringConversion(from.Type(), to.Type()) {
+		if _, err := set(to, from, s); err != nil {
+			return s.wrap(err, from.Type(), to.Type())
+		}
+		return
+	}
+
 	// Just set it if possible to assign for normal types
-	if from.Kind() != reflect.Slice && from.Kind() != reflect.Struct && from.Kind() != reflect.Map && (from.Type().AssignableTo(to.Type()) || from.Type().ConvertibleTo(to.Type())) {
+	if from.Kind() != reflect.Slice && from.Kind() != reflect.Struct && from.Kind() != reflect.Map && (from.Type().AssignableTo(to.Type()) || s.convertible(from.Type(), to.Type())) {
 		if !isPtrFrom || !opt.DeepCopy {
-			to.Set(from.Convert(to.Type()))
+			converted, err := convertValue(from, to.Type(), s)
+			if err != nil {
+				return s.wrap(err, from.Type(), to.Type())
+			}
+			to.Set(converted)
 		} else {
 			fromCopy := reflect.New(from.Type())
 			fromCopy.Set(from.Elem())
@@ -180,19 +566,48 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 
 	if from.Kind() != reflect.Slice && fromType.Kind() == reflect.Map && toType.Kind() == reflect.Map {
 		if !fromType.Key().ConvertibleTo(toType.Key()) {
-			return ErrMapKeyNotMatch
+			return s.wrap(ErrMapKeyNotMatch, fromType, toType)
+		}
+
+		// merged maps keep the entries absent from the source
+		if !s.Merge || to.IsNil() {
+			to.Set(reflect.MakeMapWithSize(toType, from.Len()))
 		}
 
-		to.Set(reflect.MakeMapWithSize(toType, from.Len()))
+		mark := s.mark()
+		for i, k := range from.MapKeys() {
+			s.reset(mark)
+			if s.canceled() {
+				return s.wrap(fmt.Errorf("copy canceled after %d of %d map entries: %w", i, from.Len(), s.ctx.Err()), fromType, toType)
+			}
 
-		for _, k := range from.MapKeys() {
+			s.push(keyElem(k), keyElem(k))
 			toKey := indirect(reflect.New(toType.Key()))
-			isSet, err := set(toKey, k, opt.DeepCopy, converters)
+			isSet, err := set(toKey, k, s)
+			if err == nil && !isSet {
+				err = fmt.Errorf("%w map, old key: %v, new key: %v", ErrNotSupported, k.Type(), toType.Key())
+			}
 			if err != nil {
-				return err
+				if err = s.handle(s.wrap(err, k.Type(), toKey.Type())); err != nil {
+					return err
+				}
+				continue
 			}
-			if !isSet {
-				return fmt.Errorf("%w map, old key: %v, new key: %v", ErrNotSupported, k.Type(), toType.Key())
+
+			// reproduce shared pointers and cycles of the source
+			if fromElem := from.MapIndex(k); s.DeepCopy && fromElem.Kind() == reflect.Ptr && !fromElem.IsNil() && toType.Elem().Kind() == reflect.Ptr {
+				toPtr := reflect.New(toType.Elem()).Elem()
+				ok, err := lookupVisited(toPtr, fromElem, s)
+				if err != nil {
+					if err = s.handle(s.wrap(err, fromElem.Type(), toPtr.Type())); err != nil {
+						return err
+					}
+					continue
+				}
+				if ok {
+					to.SetMapIndex(toKey, toPtr)
+					continue
+				}
 			}
 
 			elemType := toType.Elem()
@@ -200,14 +615,16 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 				elemType, _ = indirectType(elemType)
 			}
 			toValue := indirect(reflect.New(elemType))
-			isSet, err = set(toValue, from.MapIndex(k), opt.DeepCopy, converters)
-			if err != nil {
-				return err
+			if s.Merge {
+				if base, ok := mergeBase(to.MapIndex(toKey), from.MapIndex(k)); ok && (base.Type() == toValue.Type() || toValue.Kind() == reflect.Interface) {
+					toValue = base
+				}
 			}
-			if !isSet {
-				if err = copier(toValue.Addr().Interface(), from.MapIndex(k).Interface(), opt); err != nil {
+			if err = copyValue(toValue, from.MapIndex(k), s); err != nil {
+				if err = s.handle(err); err != nil {
 					return err
 				}
+				continue
 			}
 
 			for {
@@ -223,60 +640,118 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 	}
 
 	if from.Kind() == reflect.Slice && to.Kind() == reflect.Slice {
+		if key, ok := s.sliceKey(to.Type()); ok {
+			return copyKeyedSlice(to, from, key, s)
+		}
+
 		// Return directly if both slices are nil
 		if from.IsNil() && to.IsNil() {
 			return
 		}
 		if to.IsNil() {
-			slice := reflect.MakeSlice(reflect.SliceOf(to.Type().Elem()), from.Len(), from.Cap())
+			length := from.Len()
+			if s.SliceMode == SliceAppend {
+				length = 0
+			}
+			slice := reflect.MakeSlice(reflect.SliceOf(to.Type().Elem()), length, from.Cap())
 			to.Set(slice)
 		}
-		if fromType.ConvertibleTo(toType) {
+		if fromType.ConvertibleTo(toType) || isStructMapPair(fromType, toType) ||
+			(s.StringConversion && isStringConversion(fromType, toType)) || converters.has(from.Type().Elem(), to.Type().Elem()) {
+			// index of the first destination element, past the existing ones when appending
+			base := 0
+			if s.SliceMode == SliceAppend {
+				base = to.Len()
+			}
+
+			mark := s.mark()
 			for i := 0; i < from.Len(); i++ {
-				if to.Len() < i+1 {
+				s.reset(mark)
+				if s.canceled() {
+					return s.wrap(fmt.Errorf("copy canceled after %d of %d slice elements: %w", i, from.Len(), s.ctx.Err()), from.Type(), to.Type())
+				}
+				j := base + i
+				if to.Len() < j+1 {
 					to.Set(reflect.Append(to, reflect.New(to.Type().Elem()).Elem()))
 				}
-				isSet, err := set(to.Index(i), from.Index(i), opt.DeepCopy, converters)
+
+				s.push(indexElem(i), indexElem(j))
+				isSet, err := set(to.Index(j), from.Index(i), s)
 				if err != nil {
-					return err
+					if err = s.handle(s.wrap(err, from.Index(i).Type(), to.Index(j).Type())); err != nil {
+						return err
+					}
+					continue
 				}
 				if !isSet {
-					// ignore error while copy slice element
-					err = copier(to.Index(i).Addr().Interface(), from.Index(i).Interface(), opt)
-					if err != nil {
-						continue
+					err = copier(to.Index(j).Addr().Interface(), from.Index(i).Interface(), s)
+					if err = s.handleLenient(err); err != nil {
+						return err
 					}
 				}
 			}
+			s.reset(mark)
 
-			if to.Len() > from.Len() {
+			if s.SliceMode == SliceReplace && to.Len() > from.Len() {
 				to.SetLen(from.Len())
 			}
 			return
 		}
 	}
 
+	if from.Kind() == reflect.Struct && to.Kind() == reflect.Map {
+		return copyStructToMap(to, from, s)
+	}
+
+	if from.Kind() == reflect.Map && to.Kind() == reflect.Struct {
+		return copyMapToStruct(to, from, s)
+	}
+
 	if fromType.Kind() != reflect.Struct || toType.Kind() != reflect.Struct {
 		// skip not supported type
 		return
 	}
 
-	if len(converters) > 0 {
-		if ok, e := set(to, from, opt.DeepCopy, converters); e == nil && ok {
+	if converters.has(from.Type(), to.Type()) {
+		if ok, e := set(to, from, s); e != nil {
+			return s.handleLenient(s.wrap(e, from.Type(), to.Type()))
+		} else if ok {
 			// converter supported
 			return
 		}
 	}
 
+	// index of the first destination element, past the existing ones when appending
+	base := 0
 	if from.Kind() == reflect.Slice || to.Kind() == reflect.Slice {
 		isSlice = true
 		if from.Kind() == reflect.Slice {
 			amount = from.Len()
 		}
+		if to.Kind() == reflect.Slice && s.SliceMode == SliceAppend {
+			base = to.Len()
+		}
 	}
 
+	mark := s.mark()
 	for i := 0; i < amount; i++ {
+		s.reset(mark)
+		j := base + i
+		if isSlice {
+			if s.canceled() {
+				return s.wrap(fmt.Errorf("copy canceled after %d of %d slice elements: %w", i, amount, s.ctx.Err()), from.Type(), to.Type())
+			}
+
+			if from.Kind() == reflect.Slice {
+				s.push(indexElem(i), indexElem(j))
+			} else {
+				s.push(noElem(), indexElem(j))
+			}
+		}
+
 		var dest, source reflect.Value
+		// whether dest is an element of the destination slice, merged in place
+		inPlace := false
 
 		if isSlice {
 			// source
@@ -286,26 +761,35 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 				source = indirect(from)
 			}
 			// dest
-			dest = indirect(reflect.New(toType).Elem())
+			if existing, ok := mergedElem(to, j, toType, s); ok {
+				dest, inPlace = existing, true
+			} else {
+				dest = indirect(reflect.New(toType).Elem())
+			}
 		} else {
 			source = indirect(from)
 			dest = indirect(to)
 		}
 
-		if len(converters) > 0 {
-			if ok, e := set(dest, source, opt.DeepCopy, converters); e == nil && ok {
-				if isSlice {
+		if source.IsValid() && converters.has(source.Type(), dest.Type()) {
+			if ok, e := set(dest, source, s); e != nil {
+				if err = s.handleLenient(s.wrap(e, source.Type(), dest.Type())); err != nil {
+					return err
+				}
+				continue
+			} else if ok {
+				if isSlice && !inPlace {
 					// FIXME: maybe should check the other types?
+					elem := dest
 					if to.Type().Elem().Kind() == reflect.Ptr {
-						to.Index(i).Set(dest.Addr())
+						elem = dest.Addr()
+					}
+					if to.Len() < j+1 {
+						to.Set(reflect.Append(to, elem))
 					} else {
-						if to.Len() < i+1 {
-							reflect.Append(to, dest)
-						} else {
-							to.Index(i).Set(dest)
-						}
+						to.Index(j).Set(elem)
 					}
-				} else {
+				} else if !isSlice {
 					to.Set(dest)
 				}
 
@@ -320,162 +804,144 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 			dest = indirect(reflect.New(toType))
 		}
 
-		// Get tag options
-		flgs, err := getFlags(dest, source, toType, fromType)
+		// Get the precompiled copy plan
+		pln, err := s.plan(fromType, toType)
 		if err != nil {
-			return err
+			if err = s.handle(s.wrap(err, fromType, toType)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var copied []bool
+		if len(pln.must) > 0 {
+			copied = make([]bool, len(pln.must))
+		}
+
+		if err := callBeforeCopy(dest, source, s); err != nil {
+			if err = s.handle(s.wrap(err, fromType, toType)); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// check source
 		if source.IsValid() {
 			copyUnexportedStructFields(dest, source)
+			s.enterStruct(dest.Type())
 
 			// Copy from source field to dest field or method
-			fromTypeFields := deepFields(fromType)
-			for _, field := range fromTypeFields {
-				name := field.Name
-
-				// Get bit flags for field
-				fieldFlags := flgs.BitFlags[name]
-
-				// Check if we should ignore copying
-				if (fieldFlags & tagIgnore) != 0 {
+			fieldMark := s.mark()
+		fields:
+			for i := range pln.fields {
+				s.reset(fieldMark)
+				step := &pln.fields[i]
+
+				fromField, err := source.FieldByIndexErr(step.srcIndex)
+				if err != nil || shouldIgnore(fromField, opt.IgnoreEmpty) {
 					continue
 				}
 
-				fieldNamesMapping := getFieldNamesMapping(mappings, fromType, toType)
-
-				srcFieldName, destFieldName := getFieldName(name, flgs, fieldNamesMapping)
-				if fromField := fieldByNameOrZeroValue(source, srcFieldName); fromField.IsValid() && !shouldIgnore(fromField, opt.IgnoreEmpty) {
-					// process for nested anonymous field
-					destFieldNotSet := false
-					if f, ok := dest.Type().FieldByName(destFieldName); ok {
-						// only initialize parent embedded struct pointer in the path
-						for idx := range f.Index[:len(f.Index)-1] {
-							destField := dest.FieldByIndex(f.Index[:idx+1])
-
-							if destField.Kind() != reflect.Ptr {
-								continue
-							}
-
-							if !destField.IsNil() {
-								continue
-							}
-							if !destField.CanSet() {
-								destFieldNotSet = true
-								break
-							}
-
-							// destField is a nil pointer that can be set
-							newValue := reflect.New(destField.Type().Elem())
-							destField.Set(newValue)
-						}
-					}
+				if !step.initEmbedded(dest) {
+					break fields
+				}
 
-					if destFieldNotSet {
-						break
+				if step.destIndex == nil {
+					// try to set to method
+					if toMethod := step.destMethod(dest); toMethod.IsValid() {
+						toMethod.Call([]reflect.Value{fromField})
 					}
+					continue
+				}
 
-					toField := fieldByName(dest, destFieldName, opt.CaseSensitive)
-					if toField.IsValid() {
-						if toField.CanSet() {
-							isSet, err := set(toField, fromField, opt.DeepCopy, converters)
-							if err != nil {
-								return err
-							}
-							if !isSet {
-								if err := copier(toField.Addr().Interface(), fromField.Interface(), opt); err != nil {
-									return err
-								}
-							}
-							if fieldFlags != 0 {
-								// Note that a copy was made
-								flgs.BitFlags[name] = fieldFlags | hasCopied
-							}
-						}
-					} else {
-						// try to set to method
-						var toMethod reflect.Value
-						if dest.CanAddr() {
-							toMethod = dest.Addr().MethodByName(destFieldName)
-						} else {
-							toMethod = dest.MethodByName(destFieldName)
-						}
+				toField, err := dest.FieldByIndexErr(step.destIndex)
+				if err != nil || !toField.CanSet() {
+					continue
+				}
 
-						if toMethod.IsValid() && toMethod.Type().NumIn() == 1 && fromField.Type().AssignableTo(toMethod.Type().In(0)) {
-							toMethod.Call([]reflect.Value{fromField})
-						}
+				s.push(fieldElem(step.srcName), fieldElem(step.destName))
+				if t, ok := s.transformer(); ok {
+					err = transformValue(toField, fromField, t, s)
+				} else if step.key != nil {
+					err = copyKeyedSlice(toField, fromField, *step.key, s)
+				} else {
+					err = copyValue(toField, fromField, s)
+				}
+				if err != nil {
+					if err = s.handle(err); err != nil {
+						return err
 					}
+					continue
+				}
+				if step.must >= 0 {
+					// Note that a copy was made
+					copied[step.must] = true
 				}
 			}
 
+			s.reset(fieldMark)
+
 			// Copy from from method to dest field
-			for _, field := range deepFields(toType) {
-				name := field.Name
-				srcFieldName, destFieldName := getFieldName(name, flgs, getFieldNamesMapping(mappings, fromType, toType))
+			for i := range pln.methods {
+				step := &pln.methods[i]
 
-				var fromMethod reflect.Value
-				if source.CanAddr() {
-					fromMethod = source.Addr().MethodByName(srcFieldName)
-				} else {
-					fromMethod = source.MethodByName(srcFieldName)
+				fromMethod := step.srcMethod(source)
+				if !fromMethod.IsValid() {
+					continue
 				}
 
-				if fromMethod.IsValid() && fromMethod.Type().NumIn() == 0 && fromMethod.Type().NumOut() == 1 && !shouldIgnore(fromMethod, opt.IgnoreEmpty) {
-					if toField := fieldByName(dest, destFieldName, opt.CaseSensitive); toField.IsValid() && toField.CanSet() {
-						values := fromMethod.Call([]reflect.Value{})
-						if len(values) >= 1 {
-							_, _ = set(toField, values[0], opt.DeepCopy, converters)
+				if toField, err := dest.FieldByIndexErr(step.destIndex); err == nil && toField.CanSet() {
+					values := fromMethod.Call([]reflect.Value{})
+					if len(values) >= 1 {
+						s.push(fieldElem(step.srcName), fieldElem(step.destName))
+						_, err := set(toField, values[0], s)
+						if err = s.handleLenient(s.wrap(err, values[0].Type(), toField.Type())); err != nil {
+							return err
 						}
+						s.reset(fieldMark)
 					}
 				}
 			}
 		}
 
+		if err := callAfterCopy(dest, source, s); err != nil {
+			if err = s.handle(s.wrap(err, fromType, toType)); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if isSlice && to.Kind() == reflect.Slice {
-			if dest.Addr().Type().AssignableTo(to.Type().Elem()) {
-				if to.Len() < i+1 {
+			switch {
+			case inPlace:
+				// already merged into the destination element
+			case dest.Addr().Type().AssignableTo(to.Type().Elem()):
+				if to.Len() < j+1 {
 					to.Set(reflect.Append(to, dest.Addr()))
+				} else if elem := to.Index(j); s.SliceMode == SliceReplace && !elem.IsNil() &&
+					(s.PointerPolicy == PointerDefault || s.PointerPolicy == PointerSkipNil) {
+					// write through the existing pointer
+					elem.Elem().Set(dest)
 				} else {
-					isSet, err := set(to.Index(i), dest.Addr(), opt.DeepCopy, converters)
-					if err != nil {
-						return err
-					}
-					if !isSet {
-						// ignore error while copy slice element
-						err = copier(to.Index(i).Addr().Interface(), dest.Addr().Interface(), opt)
-						if err != nil {
-							continue
-						}
-					}
+					elem.Set(dest.Addr())
 				}
-			} else if dest.Type().AssignableTo(to.Type().Elem()) {
-				if to.Len() < i+1 {
+			case dest.Type().AssignableTo(to.Type().Elem()):
+				if to.Len() < j+1 {
 					to.Set(reflect.Append(to, dest))
 				} else {
-					isSet, err := set(to.Index(i), dest, opt.DeepCopy, converters)
-					if err != nil {
-						return err
-					}
-					if !isSet {
-						// ignore error while copy slice element
-						err = copier(to.Index(i).Addr().Interface(), dest.Interface(), opt)
-						if err != nil {
-							continue
-						}
-					}
+					to.Index(j).Set(dest)
 				}
 			}
 
-			if from.Kind() == reflect.Slice && to.Len() > from.Len() {
+			if s.SliceMode == SliceReplace && from.Kind() == reflect.Slice && to.Len() > from.Len() {
 				to.SetLen(from.Len())
 			}
 		} else if initDest {
 			to.Set(dest)
 		}
 
-		err = checkBitFlags(flgs.BitFlags)
-		if err != nil {
+		err = checkMust(pln.must, copied)
+		if err = s.handle(s.wrap(err, fromType, toType)); err != nil {
 			return err
 		}
 	}
@@ -483,6 +949,18 @@ func copier(toValue interface{}, fromValue interface{}, opt Option) (err error)
 	return
 }
 
+// copyValue copies from into to, recursing if it cannot be set directly.
+func copyValue(to, from reflect.Value, s *state) error {
+	isSet, err := set(to, from, s)
+	if err != nil {
+		return s.wrap(err, from.Type(), to.Type())
+	}
+	if !isSet {
+		return copier(to.Addr().Interface(), from.Interface(), s)
+	}
+	return nil
+}
+
 func getFieldNamesMapping(mappings map[converterPair]FieldNameMapping, fromType reflect.Type, toType reflect.Type) map[string]string {
 	var fieldNamesMapping map[string]string
 
@@ -498,16 +976,6 @@ func getFieldNamesMapping(mappings map[converterPair]FieldNameMapping, fromType
 	return fieldNamesMapping
 }
 
-func fieldByNameOrZeroValue(source reflect.Value, fieldName string) (value reflect.Value) {
-	defer func() {
-		if err := recover(); err != nil {
-			value = reflect.Value{}
-		}
-	}()
-
-	return source.FieldByName(fieldName)
-}
-
 func copyUnexportedStructFields(to, from reflect.Value) {
 	if from.Kind() != reflect.Struct || to.Kind() != reflect.Struct || !from.Type().AssignableTo(to.Type()) {
 		return
@@ -581,7 +1049,7 @@ func indirectType(reflectType reflect.Type) (_ reflect.Type, isPtr bool) {
 	return reflectType, isPtr
 }
 
-func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]TypeConverter) (bool, error) {
+func set(to, from reflect.Value, s *state) (bool, error) {
 	if !from.IsValid() {
 		return true, nil
 	}
@@ -590,18 +1058,31 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 		from = reflect.ValueOf(fromCopyValuer.CopyValue())
 	}
 
-	if ok, err := lookupAndCopyWithConverter(to, from, converters); err != nil {
+	if ok, err := lookupAndCopyWithConverter(to, from, s); err != nil {
 		return false, err
 	} else if ok {
 		return true, nil
 	}
 
+	// reproduce shared pointers and cycles of the source
+	deepCopyPtr := s.DeepCopy && to.Kind() == reflect.Ptr && from.Kind() == reflect.Ptr && !from.IsNil()
+	if deepCopyPtr {
+		if ok, err := lookupVisited(to, from, s); err != nil || ok {
+			return ok, err
+		}
+	}
+
 	if to.Kind() == reflect.Ptr {
 		// set `to` to nil if from is nil
 		if from.Kind() == reflect.Ptr && from.IsNil() {
-			to.Set(reflect.Zero(to.Type()))
+			if s.PointerPolicy != PointerSkipNil {
+				to.Set(reflect.Zero(to.Type()))
+			}
 			return true, nil
-		} else if to.IsNil() {
+		} else if s.PointerPolicy == PointerShare && from.Type().AssignableTo(to.Type()) {
+			to.Set(from)
+			return true, nil
+		} else if to.IsNil() || s.PointerPolicy == PointerClone {
 			// `from`         -> `to`
 			// sql.NullString -> *string
 			if fromValuer, ok := driverValuer(from); ok {
@@ -617,11 +1098,19 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 			// allocate new `to` variable with default value (eg. *string -> new(string))
 			to.Set(reflect.New(to.Type().Elem()))
 		}
+		if deepCopyPtr {
+			rememberVisited(to, from, s)
+		}
 		// depointer `to`
 		to = to.Elem()
+
+		// e.g. int64 -> *time.Time with a converter from int64 to time.Time
+		if ok, err := lookupAndCopyWithConverter(to, from, s); err != nil || ok {
+			return ok, err
+		}
 	}
 
-	if deepCopy {
+	if s.DeepCopy {
 		toKind := to.Kind()
 		if toKind == reflect.Interface && to.IsNil() {
 			if reflect.TypeOf(from.Interface()) != nil {
@@ -637,9 +1126,22 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 		}
 	}
 
+	// structs with hooks or transformed fields are copied field by field, and merged values element by element
+	if hasHooks(to.Type(), from.Type(), s) || s.transformsInto(to.Type()) || s.mergesInto(to.Type(), from.Type()) {
+		return false, nil
+	}
+
+	if s.StringConversion && isStringConversion(from.Type(), to.Type()) {
+		return true, convertString(to, from)
+	}
+
 	// try convert directly
-	if from.Type().ConvertibleTo(to.Type()) {
-		to.Set(from.Convert(to.Type()))
+	if s.convertible(from.Type(), to.Type()) {
+		converted, err := convertValue(from, to.Type(), s)
+		if err != nil {
+			return false, err
+		}
+		to.Set(converted)
 		return true, nil
 	}
 
@@ -690,8 +1192,12 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 			to.Set(rv)
 			return true, nil
 		}
-		if to.CanSet() && rv.Type().ConvertibleTo(to.Type()) {
-			to.Set(rv.Convert(to.Type()))
+		if to.CanSet() && s.convertible(rv.Type(), to.Type()) {
+			converted, err := convertValue(rv, to.Type(), s)
+			if err != nil {
+				return false, err
+			}
+			to.Set(converted)
 			return true, nil
 		}
 		return false, nil
@@ -699,36 +1205,24 @@ func set(to, from reflect.Value, deepCopy bool, converters map[converterPair]Typ
 
 	// from is ptr
 	if from.Kind() == reflect.Ptr {
-		return set(to, from.Elem(), deepCopy, converters)
+		return set(to, from.Elem(), s)
 	}
 
 	return false, nil
 }
 
 // lookupAndCopyWithConverter looks up the type pair, on success the TypeConverter Fn func is called to copy src to dst field.
-func lookupAndCopyWithConverter(to, from reflect.Value, converters map[converterPair]TypeConverter) (copied bool, err error) {
-	pair := converterPair{
-		SrcType: from.Type(),
-		DstType: to.Type(),
+func lookupAndCopyWithConverter(to, from reflect.Value, s *state) (copied bool, err error) {
+	cnv, ok := s.converters.lookup(from.Type(), to.Type())
+	if !ok {
+		return false, nil
 	}
 
-	if cnv, ok := converters[pair]; ok {
-		result, err := cnv.Fn(from.Interface())
-		if err != nil {
-			return false, err
-		}
-
-		if result != nil {
-			to.Set(reflect.ValueOf(result))
-		} else {
-			// in case we've got a nil value to copy
-			to.Set(reflect.Zero(to.Type()))
-		}
-
-		return true, nil
+	result, err := cnv.call(s.ctx, from.Interface())
+	if err != nil {
+		return false, err
 	}
-
-	return false, nil
+	return true, setConverted(to, result, s)
 }
 
 // parseTags Parses struct tags and returns uint8 bit flags.
@@ -742,8 +1236,12 @@ func parseTags(tag string) (flg uint8, name string, err error) {
 			flg = flg | tagMust
 		case "nopanic":
 			flg = flg | tagNoPanic
+		case "dropunmatched":
+			// see parseSliceKey
 		default:
-			if unicode.IsUpper([]rune(t)[0]) {
+			if strings.HasPrefix(t, "key=") {
+				// see parseSliceKey
+			} else if unicode.IsUpper([]rune(t)[0]) {
 				name = strings.TrimSpace(t)
 			} else {
 				err = ErrFieldNameTagStartNotUpperCase
@@ -753,8 +1251,8 @@ func parseTags(tag string) (flg uint8, name string, err error) {
 	return
 }
 
-// getTagFlags Parses struct tags for bit flags, field name.
-func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, error) {
+// getFlags Parses struct tags for bit flags, field name.
+func getFlags(toType, fromType reflect.Type) (flags, error) {
 	flgs := flags{
 		BitFlags: map[string]uint8{},
 		SrcNames: tagNameMapping{
@@ -766,16 +1264,9 @@ func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, er
 			TagToFieldName: map[string]string{},
 		},
 	}
-	var toTypeFields, fromTypeFields []reflect.StructField
-	if dest.IsValid() {
-		toTypeFields = deepFields(toType)
-	}
-	if src.IsValid() {
-		fromTypeFields = deepFields(fromType)
-	}
 
 	// Get a list dest of tags
-	for _, field := range toTypeFields {
+	for _, field := range deepFields(toType) {
 		tags := field.Tag.Get("copier")
 		if tags != "" {
 			var name string
@@ -790,7 +1281,7 @@ func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, er
 	}
 
 	// Get a list source of tags
-	for _, field := range fromTypeFields {
+	for _, field := range deepFields(fromType) {
 		tags := field.Tag.Get("copier")
 		if tags != "" {
 			var name string
@@ -806,23 +1297,6 @@ func getFlags(dest, src reflect.Value, toType, fromType reflect.Type) (flags, er
 	return flgs, nil
 }
 
-// checkBitFlags Checks flags for error or panic conditions.
-func checkBitFlags(flagsList map[string]uint8) (err error) {
-	// Check flag conditions were met
-	for name, flgs := range flagsList {
-		if flgs&hasCopied == 0 {
-			switch {
-			case flgs&tagMust != 0 && flgs&tagNoPanic != 0:
-				err = fmt.Errorf("field %s has must tag but was not copied", name)
-				return
-			case flgs&(tagMust) != 0:
-				panic(fmt.Sprintf("Field %s has must tag but was not copied", name))
-			}
-		}
-	}
-	return
-}
-
 func getFieldName(fieldName string, flgs flags, fieldNameMapping map[string]string) (srcFieldName string, destFieldName string) {
 	// get dest field name
 	if name, ok := fieldNameMapping[fieldName]; ok {
@@ -882,11 +1356,3 @@ func copyValuer(v reflect.Value) (i Valuer, ok bool) {
 	i, ok = v.Addr().Interface().(Valuer)
 	return
 }
-
-func fieldByName(v reflect.Value, name string, caseSensitive bool) reflect.Value {
-	if caseSensitive {
-		return v.FieldByName(name)
-	}
-
-	return v.FieldByNameFunc(func(n string) bool { return strings.EqualFold(n, name) })
-}
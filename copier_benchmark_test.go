@@ -3,6 +3,7 @@ package copier_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/uutw/copier"
 )
@@ -54,3 +55,43 @@ func BenchmarkJsonMarshalCopy(b *testing.B) {
 		employee.Role(user.Role)
 	}
 }
+
+func BenchmarkCopyLargeSliceOfStructs(b *testing.B) {
+	type Address struct {
+		Street string
+		City   string
+	}
+	type Row struct {
+		ID        int64
+		Name      string
+		Email     string `copier:"Mail"`
+		Age       int32
+		Score     float32
+		Active    bool
+		Tags      []string
+		Address   Address
+		CreatedAt time.Time
+	}
+	type DTO struct {
+		ID        int64
+		Name      string
+		Mail      string
+		Age       int64
+		Score     float64
+		Active    bool
+		Tags      []string
+		Address   Address
+		CreatedAt time.Time
+	}
+
+	rows := make([]Row, 10000)
+	for i := range rows {
+		rows[i] = Row{ID: int64(i), Name: "Jinzhu", Email: "jinzhu@example.org", Age: 18, Score: 1.5, Active: true, Tags: []string{"a", "b"}, Address: Address{"Main Street", "Somewhere"}, CreatedAt: time.Now()}
+	}
+
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		var dtos []DTO
+		_ = copier.Copy(&dtos, &rows)
+	}
+}
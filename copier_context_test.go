@@ -0,0 +1,114 @@
+package copier_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type ctxKey struct{}
+
+func TestCopyContextCancelSlice(t *testing.T) {
+	type Src struct {
+		ID int
+	}
+
+	type Dst struct {
+		ID string
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	opt := copier.Option{Converters: []copier.TypeConverter{{
+		SrcType: copier.Int,
+		DstType: copier.String,
+		FnContext: func(ctx context.Context, src interface{}) (interface{}, error) {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			return strconv.Itoa(src.(int)), nil
+		},
+	}}}
+
+	src := make([]Src, 10)
+	var dst []Dst
+	err := copier.CopyContext(ctx, &dst, src, opt)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error should be context.Canceled: %v", err)
+	}
+	if !strings.Contains(err.Error(), "after 3 of 10 slice elements") {
+		t.Errorf("error should tell how far the copy got: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("converter called %d times, wanted 3", calls)
+	}
+}
+
+func TestCopyContextCancelMap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := map[string]int{"a": 1, "b": 2}
+	var dst map[string]int64
+	err := copier.CopyContext(ctx, &dst, src, copier.Option{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error should be context.Canceled: %v", err)
+	}
+	if !strings.Contains(err.Error(), "after 0 of 2 map entries") {
+		t.Errorf("error should tell how far the copy got: %v", err)
+	}
+}
+
+func TestCopyContextPassedToConverter(t *testing.T) {
+	type Src struct {
+		Name string
+	}
+
+	type Dst struct {
+		Name  string
+		Extra int
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "!")
+	opt := copier.Option{Converters: []copier.TypeConverter{{
+		SrcType: copier.String,
+		DstType: copier.String,
+		FnContext: func(ctx context.Context, src interface{}) (interface{}, error) {
+			return src.(string) + ctx.Value(ctxKey{}).(string), nil
+		},
+	}}}
+
+	var dst Dst
+	if err := copier.CopyContext(ctx, &dst, Src{Name: "hello"}, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.Name != "hello!" {
+		t.Errorf("got %q, wanted %q", dst.Name, "hello!")
+	}
+
+	var dst2 Dst
+	if err := copier.New(opt).CopyContext(ctx, &dst2, Src{Name: "hi"}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst2.Name != "hi!" {
+		t.Errorf("got %q, wanted %q", dst2.Name, "hi!")
+	}
+}
+
+func TestCopyContextNotCanceled(t *testing.T) {
+	src := []int{1, 2, 3}
+	var dst []int64
+	if err := copier.CopyContext(context.Background(), &dst, src, copier.Option{}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(dst) != 3 || dst[2] != 3 {
+		t.Errorf("got %v", dst)
+	}
+}
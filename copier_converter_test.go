@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
 	"testing"
@@ -279,3 +280,295 @@ func TestValuerConv(t *testing.T) {
 		t.Errorf("copier failed from %#v to %#v", from, failedTo)
 	}
 }
+
+type converterErrSrcElem struct {
+	Field1 string
+}
+
+func (e converterErrSrcElem) Field2() string {
+	return e.Field1
+}
+
+type converterErrDestElem struct {
+	Field1 string
+	Field2 string
+}
+
+func TestCopyWithConverterErrorInSliceElement(t *testing.T) {
+	type SrcElem struct {
+		Field1 string
+	}
+
+	// convertible from SrcElem, so that DeepCopy copies each element recursively
+	type DestElem struct {
+		Field1 string
+	}
+
+	errNotANumber := errors.New("not a number")
+	converters := []copier.TypeConverter{
+		{
+			SrcType: copier.String,
+			DstType: copier.String,
+			Fn: func(src interface{}) (interface{}, error) {
+				if _, err := strconv.Atoi(src.(string)); err != nil {
+					return nil, errNotANumber
+				}
+				return src, nil
+			},
+		},
+	}
+
+	src := []SrcElem{{Field1: "1"}, {Field1: "two"}, {Field1: "3"}}
+
+	var dst []DestElem
+	err := copier.CopyWithOption(&dst, &src, copier.Option{DeepCopy: true, Converters: converters})
+	if !errors.Is(err, errNotANumber) {
+		t.Fatalf("converter error in slice element should be reported, got %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.SrcPath != "[1].Field1" {
+		t.Errorf("error should locate the slice element, got %v", err)
+	}
+
+	// the previous behavior is still available
+	dst = nil
+	err = copier.CopyWithOption(&dst, &src, copier.Option{DeepCopy: true, Converters: converters, ErrorMode: copier.ErrorModeLenient})
+	if err != nil {
+		t.Fatalf("lenient mode should ignore slice element errors, got %v", err)
+	}
+	if len(dst) != 3 || dst[0].Field1 != "1" || dst[1].Field1 != "" || dst[2].Field1 != "3" {
+		t.Errorf("got %+v", dst)
+	}
+}
+
+func TestCopyWithConverterErrorInMethodResult(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	// Field1 is empty so only the method result goes through the converter
+	src := converterErrSrcElem{}
+	var dst converterErrDestElem
+	err := copier.CopyWithOption(&dst, &src, copier.Option{
+		IgnoreEmpty: true,
+		Converters: []copier.TypeConverter{
+			{
+				SrcType: copier.String,
+				DstType: copier.String,
+				Fn: func(interface{}) (interface{}, error) {
+					return nil, errRejected
+				},
+			},
+		},
+	})
+	if !errors.Is(err, errRejected) {
+		t.Fatalf("converter error on a method result should be reported, got %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.SrcPath != "Field2" || copyErr.DstPath != "Field2" {
+		t.Errorf("error should locate the method, got %v", err)
+	}
+}
+
+type converterID int
+
+func (id converterID) String() string {
+	return fmt.Sprintf("#%d", int(id))
+}
+
+type converterLabel string
+
+func TestCopyWithFallbackConverters(t *testing.T) {
+	type Src struct {
+		ID    converterID
+		Count int64
+		Time  time.Time
+	}
+
+	type Dst struct {
+		ID    string
+		Count string
+		Time  string
+	}
+
+	stringerType := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	var dst Dst
+	err := copier.CopyWithOption(&dst, Src{ID: 7, Count: 3, Time: time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)}, copier.Option{
+		Converters: []copier.TypeConverter{
+			{
+				SrcType: stringerType,
+				DstType: copier.String,
+				Fn: func(src interface{}) (interface{}, error) {
+					return src.(fmt.Stringer).String(), nil
+				},
+			},
+			{
+				SrcKind: reflect.Int64,
+				DstKind: reflect.String,
+				Fn: func(src interface{}) (interface{}, error) {
+					return strconv.FormatInt(src.(int64), 10), nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := Dst{ID: "#7", Count: "3", Time: "2021-03-05 00:00:00 +0000 UTC"}
+	if dst != want {
+		t.Errorf("got %+v, wanted %+v", dst, want)
+	}
+}
+
+func TestConverterPrecedence(t *testing.T) {
+	type Src struct {
+		ID converterID
+	}
+
+	type Dst struct {
+		ID converterLabel
+	}
+
+	stringerType := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	named := func(name string, cnv copier.TypeConverter) copier.TypeConverter {
+		cnv.Fn = func(interface{}) (interface{}, error) {
+			return name, nil
+		}
+		return cnv
+	}
+
+	// by decreasing precedence, the last two only differ by their registration order
+	names := []string{"exact/exact", "exact/kind", "interface/exact", "interface/kind", "kind/exact", "kind/kind", "kind/kind registered last"}
+	converters := []copier.TypeConverter{
+		named(names[0], copier.TypeConverter{SrcType: converterID(0), DstType: converterLabel("")}),
+		named(names[1], copier.TypeConverter{SrcType: converterID(0), DstKind: reflect.String}),
+		named(names[2], copier.TypeConverter{SrcType: stringerType, DstType: converterLabel("")}),
+		named(names[3], copier.TypeConverter{SrcType: stringerType, DstKind: reflect.String}),
+		named(names[4], copier.TypeConverter{SrcKind: reflect.Int, DstType: converterLabel("")}),
+		named(names[5], copier.TypeConverter{SrcKind: reflect.Int, DstKind: reflect.String}),
+		named(names[6], copier.TypeConverter{SrcKind: reflect.Int, DstKind: reflect.String}),
+	}
+
+	for i := 0; i < len(converters)-1; i++ {
+		// the converters are registered in reverse order of precedence, but for the tie
+		var registered []copier.TypeConverter
+		for j := len(converters) - 2; j >= i; j-- {
+			registered = append(registered, converters[j])
+		}
+		registered = append(registered, converters[len(converters)-1])
+
+		var dst Dst
+		if err := copier.CopyWithOption(&dst, Src{ID: 1}, copier.Option{Converters: registered}); err != nil {
+			t.Fatalf("should not error: %v", err)
+		}
+		if dst.ID != converterLabel(names[i]) {
+			t.Errorf("got converter %q, wanted %q", dst.ID, names[i])
+		}
+	}
+
+	// converters of the same exact pair replace the previous ones
+	var dst Dst
+	duplicates := []copier.TypeConverter{converters[0], named("exact/exact registered last", converters[0])}
+	if err := copier.CopyWithOption(&dst, Src{ID: 1}, copier.Option{Converters: duplicates}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.ID != "exact/exact registered last" {
+		t.Errorf("got converter %q, wanted %q", dst.ID, "exact/exact registered last")
+	}
+}
+
+func TestConverterWithWrongResultType(t *testing.T) {
+	type Src struct {
+		ID converterID
+	}
+
+	type Dst struct {
+		ID converterLabel
+	}
+
+	var dst Dst
+	err := copier.CopyWithOption(&dst, Src{ID: 1}, copier.Option{Converters: []copier.TypeConverter{{
+		SrcKind: reflect.Int,
+		DstKind: reflect.String,
+		Fn: func(src interface{}) (interface{}, error) {
+			return time.Time{}, nil
+		},
+	}}})
+	if !errors.Is(err, copier.ErrInvalidConverter) {
+		t.Errorf("error should be ErrInvalidConverter: %v", err)
+	}
+
+	err = copier.New(copier.Option{Converters: []copier.TypeConverter{{
+		SrcType: converterID(0),
+		SrcKind: reflect.Int,
+		DstKind: reflect.String,
+		Fn: func(src interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	}}}).Copy(&dst, Src{ID: 1})
+	if !errors.Is(err, copier.ErrInvalidConverter) {
+		t.Errorf("error should be ErrInvalidConverter: %v", err)
+	}
+}
+
+func TestBidirectionalConverters(t *testing.T) {
+	type Src struct {
+		Age  int
+		Name string
+	}
+
+	type Dst struct {
+		Age  string
+		Name string
+	}
+
+	opt := copier.Option{
+		BidirectionalConverters: []copier.BidirectionalConverter{{
+			TypeA: copier.Int,
+			TypeB: copier.String,
+			Forward: func(a interface{}) (interface{}, error) {
+				return strconv.Itoa(a.(int)), nil
+			},
+			Backward: func(b interface{}) (interface{}, error) {
+				return strconv.Atoi(b.(string))
+			},
+		}},
+	}
+
+	var dst Dst
+	if err := copier.CopyWithOption(&dst, Src{Age: 18, Name: "Jinzhu"}, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst != (Dst{Age: "18", Name: "Jinzhu"}) {
+		t.Errorf("got %+v", dst)
+	}
+
+	var src Src
+	if err := copier.CopyWithOption(&src, dst, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if src != (Src{Age: 18, Name: "Jinzhu"}) {
+		t.Errorf("got %+v", src)
+	}
+
+	// explicit converters take precedence
+	opt.Converters = []copier.TypeConverter{{
+		SrcType: copier.Int,
+		DstType: copier.String,
+		Fn: func(interface{}) (interface{}, error) {
+			return "explicit", nil
+		},
+	}}
+	if err := copier.CopyWithOption(&dst, src, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.Age != "explicit" {
+		t.Errorf("got %q, wanted %q", dst.Age, "explicit")
+	}
+
+	err := copier.New(copier.Option{BidirectionalConverters: []copier.BidirectionalConverter{{TypeA: copier.Int, TypeB: copier.String}}}).Copy(&dst, src)
+	if !errors.Is(err, copier.ErrInvalidConverter) {
+		t.Errorf("error should be ErrInvalidConverter: %v", err)
+	}
+}
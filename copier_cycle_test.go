@@ -0,0 +1,140 @@
+package copier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type cycleNode struct {
+	Value int
+	Prev  *cycleNode
+	Next  *cycleNode
+}
+
+type cycleTree struct {
+	Name     string
+	Parent   *cycleTree
+	Children []*cycleTree
+	Siblings map[string]*cycleTree
+}
+
+func TestDeepCopyDoublyLinkedList(t *testing.T) {
+	first := &cycleNode{Value: 1}
+	second := &cycleNode{Value: 2, Prev: first}
+	third := &cycleNode{Value: 3, Prev: second, Next: first}
+	first.Next, second.Next, first.Prev = second, third, third
+
+	var copied cycleNode
+	if err := copier.CopyWithOption(&copied, first, copier.Option{DeepCopy: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	node := &copied
+	for i := 1; i <= 3; i++ {
+		if node.Value != i {
+			t.Errorf("node %d: got value %d", i, node.Value)
+		}
+		if node == first || node == second || node == third {
+			t.Fatalf("node %d should be a copy", i)
+		}
+		if node.Next.Prev != node || node.Prev.Next != node {
+			t.Fatalf("node %d: links should be reproduced", i)
+		}
+		node = node.Next
+	}
+	if node != &copied {
+		t.Errorf("the cycle should lead back to the copy of the first node")
+	}
+}
+
+func TestDeepCopyTreeWithParents(t *testing.T) {
+	root := &cycleTree{Name: "root"}
+	left := &cycleTree{Name: "left", Parent: root}
+	right := &cycleTree{Name: "right", Parent: root}
+	root.Children = []*cycleTree{left, right}
+	left.Siblings = map[string]*cycleTree{"right": right}
+
+	copied := &cycleTree{}
+	if err := copier.CopyWithOption(copied, root, copier.Option{DeepCopy: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	if copied == root || len(copied.Children) != 2 {
+		t.Fatalf("got %+v", copied)
+	}
+	for _, child := range copied.Children {
+		if child == left || child == right {
+			t.Errorf("child %s should be a copy", child.Name)
+		}
+		if child.Parent != copied {
+			t.Errorf("child %s should point to the copied root", child.Name)
+		}
+	}
+	if sibling := copied.Children[0].Siblings["right"]; sibling != copied.Children[1] {
+		t.Errorf("map values should share the copies of the pointers, got %+v", sibling)
+	}
+}
+
+func TestDeepCopySharedPointers(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+	type Pair struct {
+		A, B *Item
+		List []*Item
+	}
+
+	item := &Item{Name: "shared"}
+	from := Pair{A: item, B: item, List: []*Item{item, {Name: "other"}}}
+
+	var to Pair
+	if err := copier.CopyWithOption(&to, from, copier.Option{DeepCopy: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if to.A == item {
+		t.Fatalf("pointer should be copied")
+	}
+	if to.A != to.B || to.List[0] != to.A {
+		t.Errorf("shared pointers should stay shared")
+	}
+	if to.List[1] == to.A || to.List[1].Name != "other" {
+		t.Errorf("got %+v", to.List[1])
+	}
+
+	var shallow Pair
+	if err := copier.Copy(&shallow, from); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if shallow.List[0] != item {
+		t.Errorf("pointers should not be copied without DeepCopy")
+	}
+}
+
+func TestDeepCopyErrorOnCycle(t *testing.T) {
+	node := &cycleNode{Value: 1}
+	node.Next = node
+
+	var copied cycleNode
+	err := copier.CopyWithOption(&copied, node, copier.Option{DeepCopy: true, ErrorOnCycle: true})
+	if !errors.Is(err, copier.ErrCycleDetected) {
+		t.Fatalf("error should be ErrCycleDetected: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.SrcPath != "Next" {
+		t.Errorf("error should locate the cycle: %v", err)
+	}
+
+	// shared pointers are not cycles
+	item := &cycleNode{Value: 2}
+	list := []*cycleNode{item, item}
+	var copiedList []*cycleNode
+	if err := copier.CopyWithOption(&copiedList, list, copier.Option{DeepCopy: true, ErrorOnCycle: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if copiedList[0] != copiedList[1] {
+		t.Errorf("shared pointers should stay shared")
+	}
+}
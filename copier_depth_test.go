@@ -0,0 +1,107 @@
+package copier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type depthNode struct {
+	Value int
+	Next  *depthNode
+}
+
+type depthTree struct {
+	Children []depthTree
+}
+
+func TestMaxDepthNestedSlices(t *testing.T) {
+	from := [][][]int{{{1, 2}, {3}}}
+
+	var to [][][]int64
+	if err := copier.CopyWithOption(&to, from, copier.Option{MaxDepth: 2}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if to[0][1][0] != 3 {
+		t.Errorf("got %v", to)
+	}
+
+	to = nil
+	err := copier.CopyWithOption(&to, from, copier.Option{MaxDepth: 1})
+	if !errors.Is(err, copier.ErrMaxDepthExceeded) {
+		t.Fatalf("error should be ErrMaxDepthExceeded: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.DstPath != "[0][0]" {
+		t.Errorf("error should name the path where the limit was hit: %v", err)
+	}
+}
+
+func TestMaxDepthNestedMaps(t *testing.T) {
+	from := map[string]map[string]map[string]int{"a": {"b": {"c": 1}}}
+
+	var to map[string]map[string]map[string]int64
+	err := copier.CopyWithOption(&to, from, copier.Option{MaxDepth: 1})
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || !errors.Is(err, copier.ErrMaxDepthExceeded) {
+		t.Fatalf("error should be a *CopyError with ErrMaxDepthExceeded: %v", err)
+	}
+	if copyErr.SrcPath != "[a][b]" {
+		t.Errorf("got path %q", copyErr.SrcPath)
+	}
+}
+
+func TestMaxDepthPointerChain(t *testing.T) {
+	head := &depthNode{}
+	node := head
+	for i := 1; i < 100; i++ {
+		node.Next = &depthNode{Value: i}
+		node = node.Next
+	}
+
+	var to depthNode
+	err := copier.CopyWithOption(&to, head, copier.Option{DeepCopy: true, MaxDepth: 10})
+	if !errors.Is(err, copier.ErrMaxDepthExceeded) {
+		t.Fatalf("error should be ErrMaxDepthExceeded: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.DstPath != "Next.Next.Next.Next.Next.Next.Next.Next.Next.Next.Next" {
+		t.Errorf("error should name the path where the limit was hit: %v", err)
+	}
+
+	to = depthNode{}
+	if err := copier.CopyWithOption(&to, head, copier.Option{DeepCopy: true, MaxDepth: 100}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if to.Next.Next.Next.Value != 3 {
+		t.Errorf("got %+v", to.Next.Next.Next)
+	}
+}
+
+func TestMaxDepthCollectAll(t *testing.T) {
+	from := depthTree{Children: []depthTree{
+		{Children: []depthTree{{}}},
+		{},
+		{Children: []depthTree{{}}},
+	}}
+
+	var to depthTree
+	err := copier.CopyWithOption(&to, from, copier.Option{MaxDepth: 2, DeepCopy: true, ErrorMode: copier.ErrorModeCollectAll})
+	if !errors.Is(err, copier.ErrMaxDepthExceeded) {
+		t.Fatalf("error should be ErrMaxDepthExceeded: %v", err)
+	}
+	if errs := err.(interface{ Unwrap() []error }).Unwrap(); len(errs) != 3 {
+		t.Errorf("got %d errors, wanted 3: %v", len(errs), err)
+	}
+	if len(to.Children) != 3 {
+		t.Errorf("got %+v", to)
+	}
+
+	err = copier.CopyWithOption(&to, from, copier.Option{MaxDepth: -1})
+	if !errors.Is(err, copier.ErrInvalidOption) {
+		t.Errorf("error should be ErrInvalidOption: %v", err)
+	}
+}
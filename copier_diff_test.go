@@ -0,0 +1,235 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type diffAddress struct {
+	City   string
+	Street string
+}
+
+type diffBase struct {
+	ID int
+}
+
+type diffUser struct {
+	diffBase
+	Name     string
+	Age      int
+	Email    string `copier:"Mail"`
+	Password string `copier:"-"`
+	Address  *diffAddress
+	Tags     []string
+}
+
+type diffUserForm struct {
+	ID       int
+	FullName string
+	Age      string
+	Mail     string
+	Password string
+	Address  diffAddress
+	Tags     []string
+}
+
+func TestDiff(t *testing.T) {
+	a := diffUser{
+		diffBase: diffBase{ID: 1},
+		Name:     "jinzhu",
+		Age:      18,
+		Email:    "a@example.com",
+		Password: "secret",
+		Address:  &diffAddress{City: "Paris", Street: "Main"},
+		Tags:     []string{"a"},
+	}
+	b := diffUserForm{
+		ID:       1,
+		FullName: "Jinzhu",
+		Age:      "19",
+		Mail:     "b@example.com",
+		Password: "changed",
+		Address:  diffAddress{City: "Lyon", Street: "Main"},
+		Tags:     []string{"a", "b"},
+	}
+
+	opt := copier.Option{
+		StringConversion: true,
+		FieldNameMapping: []copier.FieldNameMapping{{SrcType: diffUserForm{}, DstType: diffUser{}, Mapping: map[string]string{"FullName": "Name"}}},
+	}
+	changes, err := copier.Diff(&a, &b, opt)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := []copier.Change{
+		{Path: "Name", Old: "jinzhu", New: "Jinzhu"},
+		{Path: "Age", Old: 18, New: 19},
+		{Path: "Email", Old: "a@example.com", New: "b@example.com"},
+		{Path: "Address.City", Old: "Paris", New: "Lyon"},
+		{Path: "Tags", Old: []string{"a"}, New: []string{"a", "b"}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %+v, want %+v", changes, want)
+	}
+
+	// Copy agrees with Diff
+	if err := copier.CopyWithOption(&a, &b, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if changes, err := copier.Diff(&a, &b, opt); err != nil || len(changes) != 0 {
+		t.Errorf("nothing should change after the copy, got %+v, %v", changes, err)
+	}
+}
+
+func TestDiffNilPointer(t *testing.T) {
+	changes, err := copier.Diff(&diffUser{}, &diffUserForm{Address: diffAddress{City: "Lyon"}}, copier.Option{})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != "Address" || changes[0].Old != (*diffAddress)(nil) {
+		t.Fatalf("got %+v", changes)
+	}
+	if to, ok := changes[0].New.(*diffAddress); !ok || to.City != "Lyon" {
+		t.Errorf("got %+v", changes[0].New)
+	}
+}
+
+func TestDiffOptions(t *testing.T) {
+	a := diffUser{Name: "jinzhu", Age: 18}
+
+	// empty values are not copied
+	changes, err := copier.Diff(&a, &diffUser{Age: 19}, copier.Option{IgnoreEmpty: true})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !reflect.DeepEqual(changes, []copier.Change{{Path: "Age", Old: 18, New: 19}}) {
+		t.Errorf("got %+v", changes)
+	}
+
+	// converters are applied to the new values
+	opt := copier.Option{Converters: []copier.TypeConverter{copier.Converter(strconv.Atoi)}}
+	changes, err = copier.Diff(&a, &diffUserForm{Age: "18"}, opt)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	for _, c := range changes {
+		if c.Path == "Age" {
+			t.Errorf("converted value should be equal, got %+v", c)
+		}
+	}
+
+	_, err = copier.Diff(&a, &diffUserForm{Age: "old"}, opt)
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.DstPath != "Age" {
+		t.Errorf("error should locate the field: %v", err)
+	}
+
+	if _, err := copier.Diff(nil, &a, copier.Option{}); !errors.Is(err, copier.ErrInvalidCopyDestination) {
+		t.Errorf("error should be ErrInvalidCopyDestination: %v", err)
+	}
+}
+
+type diffModes struct {
+	M     map[string]int
+	P     *diffAddress
+	S     []string
+	Items []keyedItem `copier:"key=ID"`
+}
+
+func TestDiffModes(t *testing.T) {
+	a := diffModes{
+		M:     map[string]int{"k": 1},
+		P:     &diffAddress{City: "Paris"},
+		S:     []string{"a"},
+		Items: []keyedItem{{ID: 1, Name: "a", Price: 10}},
+	}
+	b := diffModes{
+		M:     map[string]int{"q": 2},
+		S:     []string{"b"},
+		Items: []keyedItem{{ID: 1, Name: "A", Price: 10}},
+	}
+
+	tests := []struct {
+		name string
+		opt  copier.Option
+		want []copier.Change
+	}{
+		{
+			name: "merge",
+			opt:  copier.Option{Merge: true, PointerPolicy: copier.PointerSkipNil},
+			want: []copier.Change{
+				{Path: "M", Old: map[string]int{"k": 1}, New: map[string]int{"k": 1, "q": 2}},
+				{Path: "S", Old: []string{"a"}, New: []string{"b"}},
+				{Path: "Items", Old: []keyedItem{{ID: 1, Name: "a", Price: 10}}, New: []keyedItem{{ID: 1, Name: "A", Price: 10}}},
+			},
+		},
+		{
+			name: "skip nil pointers",
+			opt:  copier.Option{PointerPolicy: copier.PointerSkipNil},
+			want: []copier.Change{
+				{Path: "M", Old: map[string]int{"k": 1}, New: map[string]int{"q": 2}},
+				{Path: "S", Old: []string{"a"}, New: []string{"b"}},
+				{Path: "Items", Old: []keyedItem{{ID: 1, Name: "a", Price: 10}}, New: []keyedItem{{ID: 1, Name: "A", Price: 10}}},
+			},
+		},
+		{
+			name: "slice append",
+			opt:  copier.Option{SliceMode: copier.SliceAppend, PointerPolicy: copier.PointerSkipNil},
+			want: []copier.Change{
+				{Path: "M", Old: map[string]int{"k": 1}, New: map[string]int{"q": 2}},
+				{Path: "S", Old: []string{"a"}, New: []string{"a", "b"}},
+				{Path: "Items", Old: []keyedItem{{ID: 1, Name: "a", Price: 10}}, New: []keyedItem{{ID: 1, Name: "A", Price: 10}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := copier.Diff(&a, &b, tt.opt)
+			if err != nil {
+				t.Fatalf("should not error: %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("got %+v, want %+v", changes, tt.want)
+			}
+
+			// Copy agrees with Diff
+			to := a
+			to.M = map[string]int{"k": 1}
+			to.P = &diffAddress{City: "Paris"}
+			to.S = []string{"a"}
+			to.Items = []keyedItem{{ID: 1, Name: "a", Price: 10}}
+			if err := copier.CopyWithOption(&to, &b, tt.opt); err != nil {
+				t.Fatalf("should not error: %v", err)
+			}
+			for _, c := range changes {
+				if got := reflect.ValueOf(to).FieldByName(c.Path).Interface(); !reflect.DeepEqual(got, c.New) {
+					t.Errorf("%s: copy gives %+v, diff %+v", c.Path, got, c.New)
+				}
+			}
+			if !reflect.DeepEqual(a.M, map[string]int{"k": 1}) || len(a.S) != 1 {
+				t.Errorf("a should be untouched, got %+v", a)
+			}
+		})
+	}
+}
+
+func TestDiffSliceKeysOption(t *testing.T) {
+	a := []keyedItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	b := []keyedItemDTO{{ID: 2, Name: "B"}}
+
+	opt := copier.Option{SliceKeys: []copier.SliceKey{{Type: keyedItem{}, Field: "ID"}}}
+	changes, err := copier.Diff(&a, &b, opt)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	want := []copier.Change{{Old: a, New: []keyedItem{{ID: 1, Name: "a"}, {ID: 2, Name: "B"}}}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %+v, want %+v", changes, want)
+	}
+}
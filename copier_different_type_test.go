@@ -2,6 +2,7 @@ package copier_test
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -229,3 +230,79 @@ func TestCopyFromBaseToSqlNullWithOptionDeepCopy(t *testing.T) {
 		t.Errorf("b.H = %v, want %v", b.H, "deep")
 	}
 }
+
+func TestCopyIntToString(t *testing.T) {
+	type Src struct {
+		Name string
+		Age  int
+		Code uint8
+	}
+
+	type Dst struct {
+		Name string
+		Age  string
+		Code string
+	}
+
+	src := Src{Name: "Jinzhu", Age: 65, Code: 66}
+
+	var dst Dst
+	if err := copier.Copy(&dst, src); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst != (Dst{Name: "Jinzhu"}) {
+		t.Errorf("integers should not be converted into runes, got %+v", dst)
+	}
+
+	var str string
+	if err := copier.Copy(&str, 65); err != nil || str != "" {
+		t.Errorf("integers should not be converted into runes, got %q, %v", str, err)
+	}
+
+	dst = Dst{}
+	if err := copier.CopyWithOption(&dst, src, copier.Option{StringConversion: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst != (Dst{Name: "Jinzhu", Age: "65", Code: "66"}) {
+		t.Errorf("integers should be formatted as decimals, got %+v", dst)
+	}
+
+	dst = Dst{}
+	if err := copier.CopyWithOption(&dst, src, copier.Option{RuneConversion: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst != (Dst{Name: "Jinzhu", Age: "A", Code: "B"}) {
+		t.Errorf("integers should be converted into runes, got %+v", dst)
+	}
+
+	if err := copier.CopyWithOption(&str, 65, copier.Option{RuneConversion: true}); err != nil || str != "A" {
+		t.Errorf("got %q, %v", str, err)
+	}
+}
+
+func TestCopyConverterResultToString(t *testing.T) {
+	type Src struct {
+		Code int
+	}
+	type Dst struct {
+		Code string
+	}
+
+	converters := []copier.TypeConverter{{
+		SrcType: 0,
+		DstType: "",
+		Fn: func(src interface{}) (interface{}, error) {
+			return int32(src.(int)), nil
+		},
+	}}
+
+	var dst Dst
+	err := copier.CopyWithOption(&dst, Src{Code: 65}, copier.Option{Converters: converters})
+	if !errors.Is(err, copier.ErrInvalidConverter) || dst.Code != "" {
+		t.Errorf("converter results should not be converted into runes, got %q, %v", dst.Code, err)
+	}
+
+	if err := copier.CopyWithOption(&dst, Src{Code: 65}, copier.Option{Converters: converters, RuneConversion: true}); err != nil || dst.Code != "A" {
+		t.Errorf("got %q, %v", dst.Code, err)
+	}
+}
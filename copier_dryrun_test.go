@@ -0,0 +1,113 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type planAccount struct {
+	Name    string
+	Role    string `copier:"must,nopanic"`
+	Balance int    `copier:"-"`
+	Notes   string
+	Home    planAddress
+	Work    *planAddress
+}
+
+type planAddress struct {
+	City    string
+	Country string
+}
+
+type planCity struct {
+	City string
+}
+
+type planUpdate struct {
+	Name  string
+	Role  string
+	Home  planCity
+	Work  *planCity
+	Extra string
+}
+
+func (a *planAccount) AfterCopy(src interface{}) error {
+	a.Notes = "hook called"
+	return nil
+}
+
+func TestPlan(t *testing.T) {
+	account := &planAccount{Name: "jinzhu", Role: "dev", Balance: 10, Home: planAddress{City: "Paris", Country: "FR"}, Work: &planAddress{City: "Lyon"}}
+	update := planUpdate{Name: "jinzhu", Role: "admin", Home: planCity{City: "Nice"}, Work: &planCity{City: "Lyon"}}
+
+	p, err := copier.Plan(account, update, copier.Option{})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	wantChanges := []copier.Change{
+		{Path: "Role", Old: "dev", New: "admin"},
+		{Path: "Home.City", Old: "Paris", New: "Nice"},
+	}
+	if !reflect.DeepEqual(p.Changes, wantChanges) {
+		t.Errorf("got changes %+v, want %+v", p.Changes, wantChanges)
+	}
+
+	wantUncopied := []string{"Balance", "Notes", "Home.Country", "Work.Country"}
+	if !reflect.DeepEqual(p.Uncopied, wantUncopied) {
+		t.Errorf("got uncopied %v, want %v", p.Uncopied, wantUncopied)
+	}
+
+	// nothing is written
+	if account.Role != "dev" || account.Home.City != "Paris" || account.Notes != "" {
+		t.Errorf("destination should be untouched, got %+v", account)
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	account := &planAccount{Role: "dev"}
+
+	_, err := copier.Plan(account, planUpdate{}, copier.Option{IgnoreEmpty: true})
+	if err == nil {
+		t.Errorf("must check should fail")
+	}
+	if account.Role != "dev" {
+		t.Errorf("destination should be untouched, got %+v", account)
+	}
+
+	_, err = copier.Plan(account, planUpdate{Role: "admin"}, copier.Option{SliceMode: copier.SliceMode(42)})
+	if !errors.Is(err, copier.ErrInvalidOption) {
+		t.Errorf("error should be ErrInvalidOption: %v", err)
+	}
+
+	if _, err := copier.Plan(nil, planUpdate{}, copier.Option{}); !errors.Is(err, copier.ErrInvalidCopyDestination) {
+		t.Errorf("error should be ErrInvalidCopyDestination: %v", err)
+	}
+}
+
+func TestPlanRegisteredConverter(t *testing.T) {
+	defer copier.ResetConverters(copier.Converters()...)
+
+	err := copier.RegisterConverter(copier.Converter(func(s string) (string, error) { return s + "!", nil }))
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	type Named struct {
+		Name string
+	}
+	to := Named{Name: "a"}
+	p, err := copier.Plan(&to, Named{Name: "b"}, copier.Option{})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if err := copier.Copy(&to, Named{Name: "b"}); err != nil || to.Name != "b!" {
+		t.Fatalf("got %q, %v", to.Name, err)
+	}
+	if want := []copier.Change{{Path: "Name", Old: "a", New: to.Name}}; !reflect.DeepEqual(p.Changes, want) {
+		t.Errorf("got %+v, want %+v", p.Changes, want)
+	}
+}
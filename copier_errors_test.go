@@ -0,0 +1,211 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+func TestCopyErrorPath(t *testing.T) {
+	type Item struct {
+		Price float64
+	}
+	type Order struct {
+		Items []Item
+	}
+	type Cart struct {
+		Orders []Order
+	}
+
+	type ItemDTO struct {
+		Price string
+		Note  string
+	}
+	type OrderDTO struct {
+		Lines []ItemDTO `copier:"Items"`
+		Note  string
+	}
+	type CartDTO struct {
+		Orders []OrderDTO
+	}
+
+	errBadPrice := errors.New("bad price")
+	src := Cart{Orders: make([]Order, 5)}
+	for i := range src.Orders {
+		src.Orders[i].Items = make([]Item, 10)
+	}
+	src.Orders[3].Items[7].Price = -1
+
+	var dst CartDTO
+	err := copier.CopyWithOption(&dst, &src, copier.Option{Converters: []copier.TypeConverter{{
+		SrcType: copier.Float64,
+		DstType: copier.String,
+		Fn: func(src interface{}) (interface{}, error) {
+			if src.(float64) < 0 {
+				return nil, errBadPrice
+			}
+			return "ok", nil
+		},
+	}}})
+	if !errors.Is(err, errBadPrice) {
+		t.Fatalf("error should wrap the converter error: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) {
+		t.Fatalf("error should be a *CopyError: %v", err)
+	}
+	if copyErr.SrcPath != "Orders[3].Items[7].Price" {
+		t.Errorf("got source path %q", copyErr.SrcPath)
+	}
+	if copyErr.DstPath != "Orders[3].Lines[7].Price" {
+		t.Errorf("got destination path %q", copyErr.DstPath)
+	}
+	if copyErr.SrcType != reflect.TypeOf(float64(0)) || copyErr.DstType != reflect.TypeOf("") {
+		t.Errorf("got types %v and %v", copyErr.SrcType, copyErr.DstType)
+	}
+	if !strings.Contains(err.Error(), "Orders[3].Items[7].Price") || !strings.Contains(err.Error(), errBadPrice.Error()) {
+		t.Errorf("error message should contain the path and the cause: %v", err)
+	}
+}
+
+func TestCopyErrorSentinel(t *testing.T) {
+	type Src struct {
+		Values map[string]map[int64]int
+	}
+
+	type Dst struct {
+		Values map[string]map[struct{}]int
+	}
+
+	src := Src{Values: map[string]map[int64]int{"a": {1: 1}}}
+	var dst Dst
+	err := copier.Copy(&dst, &src)
+	if !errors.Is(err, copier.ErrMapKeyNotMatch) {
+		t.Fatalf("error should be ErrMapKeyNotMatch: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) {
+		t.Fatalf("error should be a *CopyError: %v", err)
+	}
+	if copyErr.SrcPath != "Values[a]" || copyErr.DstPath != "Values[a]" {
+		t.Errorf("got paths %q and %q", copyErr.SrcPath, copyErr.DstPath)
+	}
+}
+
+func TestErrorMode(t *testing.T) {
+	type Item struct {
+		Name  string
+		Price float64
+	}
+
+	type ItemDTO struct {
+		Name  string
+		Price float64
+	}
+
+	errNegative := errors.New("negative price")
+	src := []Item{{"a", 1}, {"b", -1}, {"c", 3}, {"d", -4}}
+	opt := copier.Option{
+		DeepCopy: true,
+		Converters: []copier.TypeConverter{{
+			SrcType: copier.Float64,
+			DstType: copier.Float64,
+			Fn: func(src interface{}) (interface{}, error) {
+				if src.(float64) < 0 {
+					return nil, errNegative
+				}
+				return src, nil
+			},
+		}},
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		opt := opt
+		opt.ErrorMode = copier.ErrorModeLenient
+
+		var dst []ItemDTO
+		if err := copier.CopyWithOption(&dst, src, opt); err != nil {
+			t.Fatalf("slice element errors should be ignored: %v", err)
+		}
+		if len(dst) != len(src) {
+			t.Errorf("got %d elements, wanted %d", len(dst), len(src))
+		}
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		opt := opt
+		opt.ErrorMode = copier.ErrorModeFailFast
+
+		var dst []ItemDTO
+		err := copier.CopyWithOption(&dst, src, opt)
+		var copyErr *copier.CopyError
+		if !errors.As(err, &copyErr) || !errors.Is(err, errNegative) {
+			t.Fatalf("error should be a *CopyError wrapping the converter error: %v", err)
+		}
+		if copyErr.DstPath != "[1].Price" {
+			t.Errorf("got path %q, wanted %q", copyErr.DstPath, "[1].Price")
+		}
+	})
+
+	t.Run("collect all", func(t *testing.T) {
+		opt := opt
+		opt.ErrorMode = copier.ErrorModeCollectAll
+
+		var dst []ItemDTO
+		err := copier.CopyWithOption(&dst, src, opt)
+		if !errors.Is(err, errNegative) {
+			t.Fatalf("error should wrap the converter error: %v", err)
+		}
+
+		var paths []string
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var copyErr *copier.CopyError
+			if errors.As(err, &copyErr) {
+				paths = append(paths, copyErr.DstPath)
+			}
+		}
+		if !reflect.DeepEqual(paths, []string{"[1].Price", "[3].Price"}) {
+			t.Errorf("got paths %v", paths)
+		}
+
+		if len(dst) != len(src) || dst[2].Price != 3 || dst[3].Name != "d" {
+			t.Errorf("other fields and elements should be copied, got %+v", dst)
+		}
+	})
+}
+
+func TestErrorModeCollectAllStructFields(t *testing.T) {
+	type Src struct {
+		A map[int64]int
+		B string
+		C map[int64]int
+	}
+
+	type Dst struct {
+		A map[struct{}]int
+		B string
+		C map[struct{}]int
+	}
+
+	var dst Dst
+	err := copier.CopyWithOption(&dst, Src{B: "b"}, copier.Option{ErrorMode: copier.ErrorModeCollectAll})
+	if !errors.Is(err, copier.ErrMapKeyNotMatch) {
+		t.Fatalf("error should be ErrMapKeyNotMatch: %v", err)
+	}
+	if !strings.Contains(err.Error(), "copy A") || !strings.Contains(err.Error(), "copy C") {
+		t.Errorf("error should list both fields: %v", err)
+	}
+	if dst.B != "b" {
+		t.Errorf("got %q, wanted %q", dst.B, "b")
+	}
+
+	err = copier.CopyWithOption(&dst, Src{}, copier.Option{ErrorMode: copier.ErrorMode(100)})
+	if !errors.Is(err, copier.ErrInvalidOption) {
+		t.Errorf("error should be ErrInvalidOption: %v", err)
+	}
+}
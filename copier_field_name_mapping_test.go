@@ -1,6 +1,7 @@
 package copier_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -46,3 +47,92 @@ func TestCustomFieldName(t *testing.T) {
 		t.Error("copy address failed.")
 	}
 }
+
+func TestCustomFieldNameChangesBetweenCopies(t *testing.T) {
+	type User1 struct {
+		First  string
+		Second string
+	}
+
+	type User2 struct {
+		Name string
+	}
+
+	u1 := User1{First: "first", Second: "second"}
+	for _, name := range []string{"First", "Second", "First"} {
+		var u2 User2
+		err := copier.CopyWithOption(&u2, u1, copier.Option{FieldNameMapping: []copier.FieldNameMapping{
+			{SrcType: u1, DstType: u2, Mapping: map[string]string{name: "Name"}},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := reflect.ValueOf(u1).FieldByName(name).String()
+		if u2.Name != want {
+			t.Errorf("mapping %s: got %q, wanted %q", name, u2.Name, want)
+		}
+	}
+
+	var u2 User2
+	if err := copier.Copy(&u2, u1); err != nil {
+		t.Fatal(err)
+	}
+	if u2.Name != "" {
+		t.Errorf("copy without mapping should not set Name, got %q", u2.Name)
+	}
+}
+
+func TestBidirectionalFieldNameMapping(t *testing.T) {
+	type User1 struct {
+		ID   int64
+		Name string
+		Note string
+	}
+
+	type User2 struct {
+		ID2   int64
+		Name2 string
+		Note  string
+	}
+
+	opt := copier.Option{FieldNameMapping: []copier.FieldNameMapping{
+		{SrcType: User1{}, DstType: User2{}, Mapping: map[string]string{"ID": "ID2", "Name": "Name2"}, Bidirectional: true},
+	}}
+
+	u1 := User1{ID: 1, Name: "Jinzhu", Note: "note"}
+	var u2 User2
+	if err := copier.CopyWithOption(&u2, u1, opt); err != nil {
+		t.Fatal(err)
+	}
+	if u2 != (User2{ID2: 1, Name2: "Jinzhu", Note: "note"}) {
+		t.Errorf("got %+v", u2)
+	}
+
+	var back User1
+	if err := copier.CopyWithOption(&back, u2, opt); err != nil {
+		t.Fatal(err)
+	}
+	if back != u1 {
+		t.Errorf("got %+v, wanted %+v", back, u1)
+	}
+
+	// an explicit mapping takes precedence over the inverse one
+	opt.FieldNameMapping = append(opt.FieldNameMapping, copier.FieldNameMapping{
+		SrcType: User2{}, DstType: User1{}, Mapping: map[string]string{"ID2": "ID"},
+	})
+	back = User1{}
+	if err := copier.CopyWithOption(&back, u2, opt); err != nil {
+		t.Fatal(err)
+	}
+	if back != (User1{ID: 1, Note: "note"}) {
+		t.Errorf("got %+v", back)
+	}
+
+	err := copier.New(copier.Option{FieldNameMapping: []copier.FieldNameMapping{
+		{SrcType: User1{}, DstType: User2{}, Mapping: map[string]string{"ID": "ID2", "Name": "ID2"}, Bidirectional: true},
+	}}).Copy(&u2, u1)
+	if !errors.Is(err, copier.ErrInvalidFieldNameMapping) {
+		t.Errorf("error should be ErrInvalidFieldNameMapping: %v", err)
+	}
+}
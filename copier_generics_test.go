@@ -0,0 +1,195 @@
+package copier_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/uutw/copier"
+)
+
+type genericUser struct {
+	Name string
+	Age  int32
+}
+
+type genericUserDTO struct {
+	Name string
+	Age  int64
+}
+
+func TestTo(t *testing.T) {
+	user := genericUser{Name: "Jinzhu", Age: 18}
+
+	dto, err := copier.To[genericUserDTO](user)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dto.Name != user.Name || dto.Age != int64(user.Age) {
+		t.Errorf("got %+v, wanted fields of %+v", dto, user)
+	}
+
+	ptr, err := copier.To[*genericUserDTO](&user)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if ptr == nil || ptr.Name != user.Name || ptr.Age != int64(user.Age) {
+		t.Errorf("got %+v, wanted fields of %+v", ptr, user)
+	}
+}
+
+func TestToWithOption(t *testing.T) {
+	type Dst struct {
+		Name string
+	}
+
+	dst, err := copier.To[Dst](genericUser{Name: "Jinzhu"}, copier.Option{CaseSensitive: true})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.Name != "Jinzhu" {
+		t.Errorf("got %q, wanted %q", dst.Name, "Jinzhu")
+	}
+
+	_, err = copier.To[Dst](genericUser{}, copier.Option{}, copier.Option{})
+	if !errors.Is(err, copier.ErrMultipleOptions) {
+		t.Errorf("error should be ErrMultipleOptions: %v", err)
+	}
+}
+
+func TestInto(t *testing.T) {
+	dto := genericUserDTO{Name: "old"}
+	if err := copier.Into(&dto, genericUser{Name: "new", Age: 30}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dto.Name != "new" || dto.Age != 30 {
+		t.Errorf("got %+v", dto)
+	}
+
+	if err := copier.Into[genericUserDTO](nil, genericUser{}); !errors.Is(err, copier.ErrInvalidCopyDestination) {
+		t.Errorf("error should be ErrInvalidCopyDestination: %v", err)
+	}
+}
+
+func TestToInterface(t *testing.T) {
+	if _, err := copier.To[fmt.Stringer](3); !errors.Is(err, copier.ErrInvalidCopyDestination) {
+		t.Errorf("error should be ErrInvalidCopyDestination: %v", err)
+	}
+
+	var s fmt.Stringer
+	if err := copier.Into(&s, time.Second); !errors.Is(err, copier.ErrInvalidCopyDestination) {
+		t.Errorf("error should be ErrInvalidCopyDestination: %v", err)
+	}
+
+	// interfaces holding a value are copied into
+	var v interface{} = 0
+	if err := copier.Into(&v, 3); err != nil || v != 3 {
+		t.Errorf("got %v, %v", v, err)
+	}
+}
+
+func TestSliceTo(t *testing.T) {
+	users := []genericUser{{Name: "a", Age: 1}, {Name: "b", Age: 2}}
+
+	dtos, err := copier.SliceTo[genericUserDTO](users)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(dtos) != len(users) {
+		t.Fatalf("got %d elements, wanted %d", len(dtos), len(users))
+	}
+	for i := range users {
+		if dtos[i].Name != users[i].Name || dtos[i].Age != int64(users[i].Age) {
+			t.Errorf("element %d: got %+v, wanted fields of %+v", i, dtos[i], users[i])
+		}
+	}
+
+	ptrs, err := copier.SliceTo[*genericUserDTO](users)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(ptrs) != len(users) || ptrs[1] == nil || ptrs[1].Name != "b" {
+		t.Errorf("got %+v", ptrs)
+	}
+
+	nilDtos, err := copier.SliceTo[genericUserDTO]([]genericUser(nil))
+	if err != nil || nilDtos != nil {
+		t.Errorf("nil slice should give nil slice, got %v, %v", nilDtos, err)
+	}
+}
+
+func TestMapTo(t *testing.T) {
+	users := map[int32]genericUser{1: {Name: "a", Age: 1}, 2: {Name: "b", Age: 2}}
+
+	dtos, err := copier.MapTo[int64, genericUserDTO](users)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(dtos) != len(users) {
+		t.Fatalf("got %d entries, wanted %d", len(dtos), len(users))
+	}
+	for k, user := range users {
+		if dto := dtos[int64(k)]; dto.Name != user.Name || dto.Age != int64(user.Age) {
+			t.Errorf("key %d: got %+v, wanted fields of %+v", k, dto, user)
+		}
+	}
+
+	_, err = copier.MapTo[struct{}, genericUserDTO](users)
+	if !errors.Is(err, copier.ErrMapKeyNotMatch) {
+		t.Errorf("error should be ErrMapKeyNotMatch: %v", err)
+	}
+}
+
+func TestConverter(t *testing.T) {
+	type Src struct {
+		Age      string
+		Birthday time.Time
+		Duration time.Duration
+	}
+
+	type Dst struct {
+		Age      int
+		Birthday string
+		Duration string
+	}
+
+	birthday := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+	dst, err := copier.To[Dst](Src{Age: "18", Birthday: birthday, Duration: time.Minute}, copier.Option{
+		Converters: []copier.TypeConverter{
+			copier.Converter(strconv.Atoi),
+			copier.Converter(func(t time.Time) (string, error) {
+				return t.Format(time.DateOnly), nil
+			}),
+			copier.Converter(func(s fmt.Stringer) (string, error) {
+				return "stringer " + s.String(), nil
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := Dst{Age: 18, Birthday: "2000-01-02", Duration: "stringer 1m0s"}
+	if dst != want {
+		t.Errorf("got %+v, wanted %+v", dst, want)
+	}
+
+	_, err = copier.To[Dst](Src{Age: "x"}, copier.Option{Converters: []copier.TypeConverter{copier.Converter(strconv.Atoi)}})
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("error should wrap the converter error: %v", err)
+	}
+}
+
+func TestConverterValidation(t *testing.T) {
+	cnv := copier.Converter(func(s string) (string, error) {
+		return s, nil
+	})
+	cnv.DstType = time.Time{}
+
+	c := copier.New(copier.Option{Converters: []copier.TypeConverter{cnv}})
+	if !errors.Is(c.Err(), copier.ErrInvalidConverter) {
+		t.Errorf("error should be ErrInvalidConverter: %v", c.Err())
+	}
+}
@@ -0,0 +1,165 @@
+package copier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type hookUser struct {
+	FirstName string
+	LastName  string
+	Address   hookAddress
+	Friends   []hookUser
+}
+
+type hookAddress struct {
+	City string
+}
+
+type hookUserDTO struct {
+	FirstName string
+	LastName  string
+	FullName  string
+	Address   hookAddressDTO
+	Friends   []hookUserDTO
+	Events    []string
+}
+
+func (u *hookUserDTO) BeforeCopy(src interface{}) error {
+	u.Events = append(u.Events, "before")
+	return nil
+}
+
+func (u *hookUserDTO) AfterCopy(src interface{}) error {
+	if _, ok := src.(hookUser); !ok {
+		return errors.New("unexpected source")
+	}
+	u.FullName = u.FirstName + " " + u.LastName
+	u.Events = append(u.Events, "after")
+	return nil
+}
+
+var errEmptyCity = errors.New("empty city")
+
+type hookAddressDTO struct {
+	City string
+}
+
+func (a *hookAddressDTO) AfterCopy(interface{}) error {
+	if a.City == "" {
+		return errEmptyCity
+	}
+	return nil
+}
+
+func TestCopyHooks(t *testing.T) {
+	user := hookUser{
+		FirstName: "Jinzhu",
+		LastName:  "Zhang",
+		Address:   hookAddress{City: "Shanghai"},
+		Friends:   []hookUser{{FirstName: "a", LastName: "b", Address: hookAddress{City: "Beijing"}}},
+	}
+
+	var dto hookUserDTO
+	if err := copier.Copy(&dto, user); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dto.FullName != "Jinzhu Zhang" {
+		t.Errorf("got full name %q", dto.FullName)
+	}
+	if len(dto.Events) != 2 || dto.Events[0] != "before" || dto.Events[1] != "after" {
+		t.Errorf("got events %v", dto.Events)
+	}
+	if len(dto.Friends) != 1 || dto.Friends[0].FullName != "a b" {
+		t.Errorf("hooks should run on slice elements, got %+v", dto.Friends)
+	}
+
+	var dtos []hookUserDTO
+	if err := copier.Copy(&dtos, []hookUser{user, {FirstName: "x", Address: hookAddress{City: "y"}}}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(dtos) != 2 || dtos[0].FullName != "Jinzhu Zhang" || dtos[1].FullName != "x " {
+		t.Errorf("got %+v", dtos)
+	}
+
+	var skipped hookUserDTO
+	if err := copier.CopyWithOption(&skipped, user, copier.Option{SkipHooks: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if skipped.FullName != "" || len(skipped.Events) != 0 {
+		t.Errorf("hooks should be skipped, got %+v", skipped)
+	}
+}
+
+func TestCopyHooksError(t *testing.T) {
+	user := hookUser{FirstName: "Jinzhu", Friends: []hookUser{{Address: hookAddress{City: "Beijing"}}, {}}}
+	user.Address.City = "Shanghai"
+
+	var dto hookUserDTO
+	err := copier.Copy(&dto, user)
+	if !errors.Is(err, errEmptyCity) {
+		t.Fatalf("error should wrap the hook error: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.DstPath != "Friends[1].Address" {
+		t.Errorf("error should locate the nested struct: %v", err)
+	}
+
+	var address hookAddressDTO
+	if err := copier.Copy(&address, map[string]string{"City": ""}); !errors.Is(err, errEmptyCity) {
+		t.Errorf("hooks should run when copying from a map: %v", err)
+	}
+
+	if err := copier.CopyWithOption(&dto, user, copier.Option{SkipHooks: true}); err != nil {
+		t.Errorf("should not error without hooks: %v", err)
+	}
+}
+
+type hookItem struct {
+	Name   string
+	Copies int
+}
+
+func (i *hookItem) AfterCopy(interface{}) error {
+	i.Copies++
+	return nil
+}
+
+type hookOrder struct {
+	Items []hookItem
+	Main  hookItem
+}
+
+func TestCopyHooksSameType(t *testing.T) {
+	var items []hookItem
+	if err := copier.Copy(&items, []hookItem{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	for _, item := range items {
+		if item.Copies != 1 {
+			t.Errorf("AfterCopy should be called once on slice element %s, got %d", item.Name, item.Copies)
+		}
+	}
+
+	var order hookOrder
+	if err := copier.Copy(&order, hookOrder{Items: []hookItem{{Name: "a"}}, Main: hookItem{Name: "main"}}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if order.Main.Copies != 1 {
+		t.Errorf("AfterCopy should be called once on the nested struct, got %d", order.Main.Copies)
+	}
+	if len(order.Items) != 1 || order.Items[0].Copies != 1 {
+		t.Errorf("AfterCopy should be called once on the nested slice elements, got %+v", order.Items)
+	}
+
+	order = hookOrder{}
+	if err := copier.CopyWithOption(&order, hookOrder{Main: hookItem{Name: "main"}}, copier.Option{SkipHooks: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if order.Main.Copies != 0 || order.Main.Name != "main" {
+		t.Errorf("hooks should be skipped, got %+v", order.Main)
+	}
+}
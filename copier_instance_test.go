@@ -0,0 +1,154 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+func TestCopierInstance(t *testing.T) {
+	type Src struct {
+		ID   int
+		Name string
+	}
+
+	type Dst struct {
+		ID    string
+		Label string
+	}
+
+	c := copier.New(copier.Option{
+		Converters: []copier.TypeConverter{{
+			SrcType: copier.Int,
+			DstType: copier.String,
+			Fn: func(src interface{}) (interface{}, error) {
+				return strconv.Itoa(src.(int)), nil
+			},
+		}},
+		FieldNameMapping: []copier.FieldNameMapping{
+			{SrcType: Src{}, DstType: Dst{}, Mapping: map[string]string{"Name": "Label"}},
+		},
+	})
+	if err := c.Err(); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	srcs := make([]Src, 100)
+	for i := range srcs {
+		srcs[i] = Src{ID: i, Name: "name " + strconv.Itoa(i)}
+	}
+
+	var wg sync.WaitGroup
+	for i := range srcs {
+		wg.Add(1)
+		go func(src Src) {
+			defer wg.Done()
+
+			var dst Dst
+			if err := c.Copy(&dst, &src); err != nil {
+				t.Errorf("should not error: %v", err)
+				return
+			}
+			if dst.ID != strconv.Itoa(src.ID) || dst.Label != src.Name {
+				t.Errorf("got %+v, wanted fields of %+v", dst, src)
+			}
+		}(srcs[i])
+	}
+	wg.Wait()
+}
+
+func TestCopierInstancesAreIndependent(t *testing.T) {
+	type Src struct {
+		A string
+		B string
+	}
+
+	type Dst struct {
+		Name string
+	}
+
+	mapping := map[string]string{"A": "Name"}
+	fromA := copier.New(copier.Option{FieldNameMapping: []copier.FieldNameMapping{
+		{SrcType: Src{}, DstType: Dst{}, Mapping: mapping},
+	}})
+	fromB := copier.New(copier.Option{FieldNameMapping: []copier.FieldNameMapping{
+		{SrcType: Src{}, DstType: Dst{}, Mapping: map[string]string{"B": "Name"}},
+	}})
+
+	// changing the mapping after New must not affect the copier
+	delete(mapping, "A")
+	mapping["B"] = "Name"
+
+	src := Src{A: "a", B: "b"}
+	var dstA, dstB Dst
+	if err := fromA.Copy(&dstA, src); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if err := fromB.Copy(&dstB, src); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	if dstA.Name != "a" {
+		t.Errorf("got %q, wanted %q", dstA.Name, "a")
+	}
+	if dstB.Name != "b" {
+		t.Errorf("got %q, wanted %q", dstB.Name, "b")
+	}
+}
+
+func TestCopierInstanceInvalidOption(t *testing.T) {
+	c := copier.New(copier.Option{Converters: []copier.TypeConverter{{SrcType: copier.String, DstType: copier.Int}}})
+	if !errors.Is(c.Err(), copier.ErrInvalidConverter) {
+		t.Errorf("error should be ErrInvalidConverter: %v", c.Err())
+	}
+
+	var dst string
+	if err := c.Copy(&dst, "value"); !errors.Is(err, copier.ErrInvalidConverter) {
+		t.Errorf("error should be ErrInvalidConverter: %v", err)
+	}
+
+	c = copier.New(copier.Option{FieldNameMapping: []copier.FieldNameMapping{{DstType: struct{}{}}}})
+	if !errors.Is(c.Err(), copier.ErrInvalidFieldNameMapping) {
+		t.Errorf("error should be ErrInvalidFieldNameMapping: %v", c.Err())
+	}
+
+	// the legacy entry points ignore invalid entries
+	opt := copier.Option{
+		Converters:       []copier.TypeConverter{{DstType: copier.Int}, {SrcType: copier.String, DstType: copier.String}},
+		FieldNameMapping: []copier.FieldNameMapping{{DstType: struct{}{}}},
+	}
+	if err := copier.CopyWithOption(&dst, "other", opt); err != nil || dst != "other" {
+		t.Errorf("invalid entries should be ignored, got %q, %v", dst, err)
+	}
+
+	// but not invalid modes nor invalid entries of the options added since
+	tests := []struct {
+		name string
+		opt  copier.Option
+		err  error
+	}{
+		{"slice mode", copier.Option{SliceMode: copier.SliceMode(42)}, copier.ErrInvalidOption},
+		{"slice key", copier.Option{SliceKeys: []copier.SliceKey{{Field: "ID"}}}, copier.ErrInvalidOption},
+		{"field transformer", copier.Option{FieldTransformers: []copier.FieldTransformer{{DstType: struct{}{}}}}, copier.ErrInvalidFieldTransformer},
+		{"bidirectional converter", copier.Option{BidirectionalConverters: []copier.BidirectionalConverter{{TypeA: copier.Int}}}, copier.ErrInvalidConverter},
+		{"kind converter", copier.Option{Converters: []copier.TypeConverter{{SrcKind: reflect.Int, DstType: copier.String}}}, copier.ErrInvalidConverter},
+		{"bidirectional mapping", copier.Option{FieldNameMapping: []copier.FieldNameMapping{{DstType: struct{}{}, Bidirectional: true}}}, copier.ErrInvalidFieldNameMapping},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := copier.CopyWithOption(&dst, "value", tt.opt); !errors.Is(err, tt.err) {
+				t.Errorf("CopyWithOption error should be %v: %v", tt.err, err)
+			}
+			if _, err := copier.Diff(&dst, "value", tt.opt); !errors.Is(err, tt.err) {
+				t.Errorf("Diff error should be %v: %v", tt.err, err)
+			}
+			if _, err := copier.Plan(&dst, "value", tt.opt); !errors.Is(err, tt.err) {
+				t.Errorf("Plan error should be %v: %v", tt.err, err)
+			}
+		})
+	}
+}
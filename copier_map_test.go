@@ -0,0 +1,221 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/uutw/copier"
+)
+
+type mapAddress struct {
+	City    string
+	ZipCode string
+}
+
+type mapUser struct {
+	Name     string
+	Age      int
+	Email    string `copier:"Mail"`
+	Password string `copier:"-"`
+	Address  mapAddress
+	Billing  *mapAddress
+	Birthday time.Time
+}
+
+func TestCopyStructToMap(t *testing.T) {
+	birthday := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+	user := mapUser{
+		Name:     "Jinzhu",
+		Age:      18,
+		Email:    "jinzhu@example.org",
+		Password: "secret",
+		Address:  mapAddress{City: "Shanghai", ZipCode: "200000"},
+		Birthday: birthday,
+	}
+
+	var m map[string]interface{}
+	if err := copier.Copy(&m, user); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"Name":     "Jinzhu",
+		"Age":      18,
+		"Mail":     "jinzhu@example.org",
+		"Address":  map[string]interface{}{"City": "Shanghai", "ZipCode": "200000"},
+		"Billing":  nil,
+		"Birthday": birthday,
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v, wanted %#v", m, want)
+	}
+}
+
+func TestCopyStructToMapWithOption(t *testing.T) {
+	user := mapUser{Name: "Jinzhu", Address: mapAddress{City: "Shanghai"}, Billing: &mapAddress{ZipCode: "100000"}}
+
+	var m map[string]string
+	err := copier.CopyWithOption(&m, &user, copier.Option{
+		IgnoreEmpty:     true,
+		MapKeySeparator: ".",
+		FieldNameMapping: []copier.FieldNameMapping{
+			{SrcType: mapUser{}, DstType: map[string]string{}, Mapping: map[string]string{"Name": "name"}},
+		},
+		Converters: []copier.TypeConverter{{
+			SrcType: copier.Int,
+			DstType: copier.String,
+			Fn: func(src interface{}) (interface{}, error) {
+				return strconv.Itoa(src.(int)), nil
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := map[string]string{
+		"name":            "Jinzhu",
+		"Address.City":    "Shanghai",
+		"Billing.ZipCode": "100000",
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %#v, wanted %#v", m, want)
+	}
+}
+
+func TestCopyStructToMapSkipsUnsupportedValues(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  int
+		Tags []string
+		Meta map[string]int
+		When time.Time
+	}
+
+	var m map[string]string
+	if err := copier.Copy(&m, Person{Name: "Jinzhu", Age: 18, Tags: []string{"a"}, Meta: map[string]int{"a": 1}, When: time.Now()}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if want := map[string]string{"Name": "Jinzhu"}; !reflect.DeepEqual(m, want) {
+		t.Errorf("values the map cannot hold should be skipped, got %#v", m)
+	}
+}
+
+func TestCopyMapToStruct(t *testing.T) {
+	birthday := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+	m := map[string]interface{}{
+		"name":     "Jinzhu",
+		"Age":      float64(18),
+		"Mail":     "jinzhu@example.org",
+		"Password": "secret",
+		"Address":  map[string]interface{}{"City": "Shanghai"},
+		"Billing":  map[string]interface{}{"ZipCode": "100000"},
+		"Birthday": birthday,
+	}
+
+	user := mapUser{Password: "unchanged", Address: mapAddress{ZipCode: "200000"}}
+	if err := copier.Copy(&user, m); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := mapUser{
+		Name:     "Jinzhu",
+		Age:      18,
+		Email:    "jinzhu@example.org",
+		Password: "unchanged",
+		Address:  mapAddress{City: "Shanghai", ZipCode: "200000"},
+		Billing:  &mapAddress{ZipCode: "100000"},
+		Birthday: birthday,
+	}
+	if !reflect.DeepEqual(user, want) {
+		t.Errorf("got %#v, wanted %#v", user, want)
+	}
+}
+
+func TestCopyMapToStructWithOption(t *testing.T) {
+	m := map[string]string{
+		"Name":            "",
+		"Age":             "18",
+		"Address.City":    "Shanghai",
+		"Billing.ZipCode": "100000",
+	}
+
+	user := mapUser{Name: "Jinzhu"}
+	err := copier.CopyWithOption(&user, m, copier.Option{
+		CaseSensitive:   true,
+		IgnoreEmpty:     true,
+		MapKeySeparator: ".",
+		Converters: []copier.TypeConverter{{
+			SrcType: copier.String,
+			DstType: copier.Int,
+			Fn: func(src interface{}) (interface{}, error) {
+				return strconv.Atoi(src.(string))
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := mapUser{Name: "Jinzhu", Age: 18, Address: mapAddress{City: "Shanghai"}, Billing: &mapAddress{ZipCode: "100000"}}
+	if !reflect.DeepEqual(user, want) {
+		t.Errorf("got %#v, wanted %#v", user, want)
+	}
+}
+
+func TestCopyMapToStructErrors(t *testing.T) {
+	type Dst struct {
+		Name string `copier:"must,nopanic"`
+		Age  int
+	}
+
+	var dst Dst
+	err := copier.Copy(&dst, map[string]interface{}{"Age": 1})
+	if err == nil {
+		t.Errorf("should error when a must field is missing")
+	}
+
+	err = copier.Copy(&dst, map[int]interface{}{1: 1})
+	if !errors.Is(err, copier.ErrNotSupported) {
+		t.Errorf("error should be ErrNotSupported: %v", err)
+	}
+
+	errBad := errors.New("bad age")
+	err = copier.CopyWithOption(&dst, map[string]interface{}{"Name": "a", "Age": "x"}, copier.Option{Converters: []copier.TypeConverter{{
+		SrcType: copier.String,
+		DstType: copier.Int,
+		Fn: func(interface{}) (interface{}, error) {
+			return nil, errBad
+		},
+	}}})
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || !errors.Is(err, errBad) {
+		t.Fatalf("error should be a *CopyError: %v", err)
+	}
+	if copyErr.SrcPath != "[Age]" || copyErr.DstPath != "Age" {
+		t.Errorf("got paths %q and %q", copyErr.SrcPath, copyErr.DstPath)
+	}
+}
+
+func TestCopySliceOfStructsToMaps(t *testing.T) {
+	users := []mapUser{{Name: "a"}, {Name: "b"}}
+
+	maps, err := copier.SliceTo[map[string]interface{}](users)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(maps) != 2 || maps[1]["Name"] != "b" {
+		t.Fatalf("got %#v", maps)
+	}
+
+	back, err := copier.SliceTo[mapUser](maps)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !reflect.DeepEqual(back, users) {
+		t.Errorf("got %#v, wanted %#v", back, users)
+	}
+}
@@ -0,0 +1,212 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type mergeDB struct {
+	Host string
+	Port int
+}
+
+type mergeConfig struct {
+	Name   string
+	DB     mergeDB
+	Cache  *mergeDB
+	Labels map[string]string
+	Extra  map[string]interface{}
+	Hosts  []string
+}
+
+func newMergeDefaults() mergeConfig {
+	return mergeConfig{
+		Name:   "app",
+		DB:     mergeDB{Host: "localhost", Port: 5432},
+		Cache:  &mergeDB{Host: "cache", Port: 6379},
+		Labels: map[string]string{"env": "dev", "team": "a"},
+		Extra:  map[string]interface{}{"log": map[string]interface{}{"level": "info", "format": "json"}, "debug": false},
+		Hosts:  []string{"a", "b"},
+	}
+}
+
+func TestMerge(t *testing.T) {
+	override := mergeConfig{
+		DB:     mergeDB{Host: "db"},
+		Cache:  &mergeDB{Port: 1},
+		Labels: map[string]string{"env": "prod"},
+		Extra:  map[string]interface{}{"log": map[string]interface{}{"level": "debug"}},
+		Hosts:  []string{"c"},
+	}
+
+	cfg := newMergeDefaults()
+	cache := cfg.Cache
+	if err := copier.CopyWithOption(&cfg, override, copier.Option{Merge: true, IgnoreEmpty: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := mergeConfig{
+		Name:   "app",
+		DB:     mergeDB{Host: "db", Port: 5432},
+		Cache:  &mergeDB{Host: "cache", Port: 1},
+		Labels: map[string]string{"env": "prod", "team": "a"},
+		Extra:  map[string]interface{}{"log": map[string]interface{}{"level": "debug", "format": "json"}, "debug": false},
+		Hosts:  []string{"c"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("got %+v, wanted %+v", cfg, want)
+	}
+	if cfg.Cache != cache {
+		t.Errorf("the destination pointer should be merged into")
+	}
+
+	// without Merge, maps are replaced
+	cfg = newMergeDefaults()
+	if err := copier.CopyWithOption(&cfg, override, copier.Option{IgnoreEmpty: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Labels, override.Labels) {
+		t.Errorf("got %v, wanted %v", cfg.Labels, override.Labels)
+	}
+}
+
+type mergeItem struct {
+	Name string
+	Qty  int
+}
+
+type mergeItemDTO struct {
+	Name string
+	Qty  int
+	Note string
+}
+
+func TestSliceMode(t *testing.T) {
+	newDst := func() []mergeItem {
+		return []mergeItem{{"a", 1}, {"b", 2}, {"c", 3}}
+	}
+	src := []mergeItem{{Qty: 10}, {Name: "B2"}}
+	srcDTO := []mergeItemDTO{{Qty: 10}, {Name: "B2"}}
+
+	tests := []struct {
+		name string
+		opt  copier.Option
+		want []mergeItem
+	}{
+		{"replace", copier.Option{}, []mergeItem{{"", 10}, {"B2", 0}}},
+		{"append", copier.Option{SliceMode: copier.SliceAppend}, []mergeItem{{"a", 1}, {"b", 2}, {"c", 3}, {"", 10}, {"B2", 0}}},
+		{"merge index", copier.Option{SliceMode: copier.SliceMergeIndex}, []mergeItem{{"", 10}, {"B2", 0}, {"c", 3}}},
+		{"merge index with merge", copier.Option{SliceMode: copier.SliceMergeIndex, Merge: true, IgnoreEmpty: true}, []mergeItem{{"a", 10}, {"B2", 2}, {"c", 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := newDst()
+			if err := copier.CopyWithOption(&dst, src, tt.opt); err != nil {
+				t.Fatalf("should not error: %v", err)
+			}
+			if !reflect.DeepEqual(dst, tt.want) {
+				t.Errorf("got %+v, wanted %+v", dst, tt.want)
+			}
+
+			// elements of another type are copied field by field
+			dst = newDst()
+			if err := copier.CopyWithOption(&dst, srcDTO, tt.opt); err != nil {
+				t.Fatalf("should not error: %v", err)
+			}
+			if !reflect.DeepEqual(dst, tt.want) {
+				t.Errorf("got %+v, wanted %+v", dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestSliceModeFields(t *testing.T) {
+	type Src struct {
+		Tags  []string
+		Items []*mergeItem
+	}
+
+	dst := Src{Tags: []string{"a"}, Items: []*mergeItem{{"a", 1}}}
+	src := Src{Tags: []string{"b", "c"}, Items: []*mergeItem{{Name: "b"}, {"c", 3}}}
+
+	if err := copier.CopyWithOption(&dst, src, copier.Option{SliceMode: copier.SliceAppend}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Tags, []string{"a", "b", "c"}) || len(dst.Items) != 3 || dst.Items[2].Name != "c" {
+		t.Errorf("got %+v", dst)
+	}
+
+	err := copier.CopyWithOption(&dst, src, copier.Option{SliceMode: copier.SliceMode(100)})
+	if !errors.Is(err, copier.ErrInvalidOption) {
+		t.Errorf("error should be ErrInvalidOption: %v", err)
+	}
+}
+
+func TestCopySliceReplaceWritesThroughPointers(t *testing.T) {
+	type S struct {
+		A int
+	}
+	type T struct {
+		A, B int
+	}
+
+	x := &T{A: 1, B: 2}
+	to := []*T{x}
+	if err := copier.Copy(&to, []S{{A: 9}}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if to[0] != x || *x != (T{A: 9}) {
+		t.Errorf("existing element should be written through, got %+v, %+v", to[0], x)
+	}
+
+	to = []*T{x}
+	if err := copier.CopyWithOption(&to, []S{{A: 5}}, copier.Option{PointerPolicy: copier.PointerClone}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if to[0] == x || to[0].A != 5 || x.A != 9 {
+		t.Errorf("cloned pointer should replace the element, got %+v, %+v", to[0], x)
+	}
+}
+
+func TestCopySliceAppendWithConverter(t *testing.T) {
+	type CA struct {
+		V int
+	}
+	type CB struct {
+		V int
+	}
+
+	opt := copier.Option{
+		SliceMode: copier.SliceAppend,
+		Converters: []copier.TypeConverter{{
+			SrcType: CA{},
+			DstType: CB{},
+			Fn: func(src interface{}) (interface{}, error) {
+				return CB{V: src.(CA).V * 10}, nil
+			},
+		}},
+	}
+
+	values := []CB{{V: 1}}
+	if err := copier.CopyWithOption(&values, []CA{{V: 2}, {V: 3}}, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !reflect.DeepEqual(values, []CB{{V: 1}, {V: 20}, {V: 30}}) {
+		t.Errorf("got %+v", values)
+	}
+
+	var pointers []*CB
+	if err := copier.CopyWithOption(&pointers, []CA{{V: 2}}, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if err := copier.CopyWithOption(&pointers, CA{V: 3}, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(pointers) != 2 || pointers[0].V != 20 || pointers[1].V != 30 {
+		t.Errorf("got %+v", pointers)
+	}
+}
@@ -0,0 +1,223 @@
+package copier_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+func TestStrictNumeric(t *testing.T) {
+	opt := copier.Option{StrictNumeric: true}
+
+	tests := []struct {
+		name  string
+		to    interface{}
+		from  interface{}
+		lossy bool
+	}{
+		{"int64 to int32", new(int32), int64(1 << 40), true},
+		{"int64 to int32 in range", new(int32), int64(-1 << 20), false},
+		{"negative int to uint", new(uint), -1, true},
+		{"int to uint8", new(uint8), 256, true},
+		{"int to uint8 in range", new(uint8), 255, false},
+		{"uint64 to int64", new(int64), uint64(math.MaxUint64), true},
+		{"uint16 to int8", new(int8), uint16(128), true},
+		{"uint32 to uint16", new(uint16), uint32(1 << 16), true},
+		{"float with fraction to int", new(int), 1.5, true},
+		{"whole float to int", new(int), 2.0, false},
+		{"large float to int64", new(int64), 1e19, true},
+		{"negative float to uint", new(uint), -2.0, true},
+		{"NaN to int", new(int), math.NaN(), true},
+		{"float64 to float32", new(float32), 1e300, true},
+		{"float64 to float32 precision", new(float32), 0.1, true},
+		{"float64 to float32 exact", new(float32), 0.5, false},
+		{"int64 to float64 precision", new(float64), int64(1<<53 + 1), true},
+		{"int64 to float64 exact", new(float64), int64(1 << 53), false},
+		{"uint64 to float32", new(float32), uint64(1<<24 + 1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := copier.CopyWithOption(tt.to, tt.from, opt)
+			if tt.lossy != errors.Is(err, copier.ErrLossyConversion) {
+				t.Errorf("got error %v", err)
+			}
+			if !tt.lossy && err != nil {
+				t.Errorf("should not error: %v", err)
+			}
+		})
+	}
+}
+
+func TestStrictNumericPath(t *testing.T) {
+	type Item struct {
+		Quantity int64
+		Price    float64
+	}
+
+	type ItemDTO struct {
+		Quantity int32
+		Price    int
+	}
+
+	items := []Item{{Quantity: 1, Price: 2}, {Quantity: 1 << 40, Price: 3}}
+
+	var dtos []ItemDTO
+	err := copier.CopyWithOption(&dtos, items, copier.Option{StrictNumeric: true})
+	if !errors.Is(err, copier.ErrLossyConversion) {
+		t.Fatalf("error should be ErrLossyConversion: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.DstPath != "[1].Quantity" {
+		t.Errorf("error should locate the field: %v", err)
+	}
+
+	items[1] = Item{Quantity: 2, Price: 2.5}
+	err = copier.CopyWithOption(&dtos, items, copier.Option{StrictNumeric: true})
+	if !errors.As(err, &copyErr) || copyErr.DstPath != "[1].Price" {
+		t.Errorf("error should locate the field: %v", err)
+	}
+
+	// values are truncated without the option
+	dtos = nil
+	if err := copier.Copy(&dtos, items); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dtos[1].Price != 2 {
+		t.Errorf("got %+v", dtos[1])
+	}
+}
+
+func TestStringConversion(t *testing.T) {
+	type Form struct {
+		Age     string
+		Height  string
+		Score   string
+		Active  string
+		Visits  string
+		Comment string
+	}
+
+	type User struct {
+		Age     int
+		Height  float32
+		Score   *uint8
+		Active  bool
+		Visits  string
+		Comment string
+	}
+
+	opt := copier.Option{StringConversion: true}
+	form := Form{Age: "30", Height: "1.75", Score: "200", Active: "true", Visits: "3", Comment: "hi"}
+
+	var user User
+	if err := copier.CopyWithOption(&user, form, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if user.Age != 30 || user.Height != 1.75 || user.Score == nil || *user.Score != 200 || !user.Active || user.Visits != "3" || user.Comment != "hi" {
+		t.Errorf("got %+v", user)
+	}
+
+	var back Form
+	if err := copier.CopyWithOption(&back, user, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if back != form {
+		t.Errorf("got %+v, wanted %+v", back, form)
+	}
+
+	var age int64
+	if err := copier.CopyWithOption(&age, "42", opt); err != nil || age != 42 {
+		t.Errorf("got %d, %v", age, err)
+	}
+}
+
+func TestStringConversionErrors(t *testing.T) {
+	type Form struct {
+		Name  string
+		Score string
+	}
+
+	type User struct {
+		Name  string
+		Score uint8
+	}
+
+	var user User
+	for _, score := range []string{"abc", "256", "-1"} {
+		err := copier.CopyWithOption(&user, Form{Score: score}, copier.Option{StringConversion: true})
+		if !errors.Is(err, strconv.ErrSyntax) && !errors.Is(err, strconv.ErrRange) {
+			t.Errorf("score %q: error should be a parse error: %v", score, err)
+		}
+
+		var copyErr *copier.CopyError
+		if !errors.As(err, &copyErr) || copyErr.DstPath != "Score" {
+			t.Errorf("score %q: error should locate the field: %v", score, err)
+		}
+	}
+
+	// strings are not parsed without the option
+	if err := copier.Copy(&user, Form{Name: "a", Score: "1"}); err != nil || user.Score != 0 || user.Name != "a" {
+		t.Errorf("got %+v, %v", user, err)
+	}
+}
+
+func TestStrictNumericConverterResult(t *testing.T) {
+	type Src struct {
+		Level string
+	}
+	type Dst struct {
+		Level int8
+	}
+
+	converters := []copier.TypeConverter{{
+		SrcType: "",
+		DstType: int8(0),
+		Fn: func(src interface{}) (interface{}, error) {
+			return strconv.ParseInt(src.(string), 10, 64)
+		},
+	}}
+
+	var dst Dst
+	err := copier.CopyWithOption(&dst, Src{Level: "1000"}, copier.Option{Converters: converters, StrictNumeric: true})
+	if !errors.Is(err, copier.ErrLossyConversion) {
+		t.Errorf("error should be ErrLossyConversion: %v", err)
+	}
+
+	if err := copier.CopyWithOption(&dst, Src{Level: "100"}, copier.Option{Converters: converters, StrictNumeric: true}); err != nil || dst.Level != 100 {
+		t.Errorf("got %d, %v", dst.Level, err)
+	}
+}
+
+func TestStringConversionSlices(t *testing.T) {
+	opt := copier.Option{StringConversion: true}
+
+	var ints []int
+	if err := copier.CopyWithOption(&ints, []string{"5", "6"}, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(ints) != 2 || ints[0] != 5 || ints[1] != 6 {
+		t.Errorf("got %v", ints)
+	}
+
+	var strs []string
+	if err := copier.CopyWithOption(&strs, []int{7}, opt); err != nil || len(strs) != 1 || strs[0] != "7" {
+		t.Errorf("got %v, %v", strs, err)
+	}
+
+	ints = nil
+	if err := copier.CopyWithOption(&ints, []string{"5", "x"}, opt); !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("parse error should be returned: %v", err)
+	}
+
+	// element converters are used too
+	converters := []copier.TypeConverter{copier.Converter(strconv.Atoi)}
+	ints = nil
+	if err := copier.CopyWithOption(&ints, []string{"8"}, copier.Option{Converters: converters}); err != nil || len(ints) != 1 || ints[0] != 8 {
+		t.Errorf("got %v, %v", ints, err)
+	}
+}
@@ -0,0 +1,110 @@
+package copier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type pointerInner struct {
+	Name string
+}
+
+type pointerSrc struct {
+	Inner *pointerInner
+}
+
+type pointerDst struct {
+	Inner *pointerInner
+	Extra int
+}
+
+func TestPointerPolicy(t *testing.T) {
+	const (
+		setNil     = "nil"
+		shared     = "shared with the source"
+		updated    = "previous pointer updated"
+		allocated  = "new pointer"
+		untouched  = "previous pointer untouched"
+		withSrc    = true
+		withoutSrc = false
+		withDst    = true
+		withoutDst = false
+	)
+
+	tests := []struct {
+		policy copier.PointerPolicy
+		src    bool
+		dst    bool
+		want   string
+	}{
+		{copier.PointerDefault, withoutSrc, withoutDst, setNil},
+		{copier.PointerDefault, withoutSrc, withDst, setNil},
+		{copier.PointerDefault, withSrc, withoutDst, allocated},
+		{copier.PointerDefault, withSrc, withDst, updated},
+
+		{copier.PointerShare, withoutSrc, withoutDst, setNil},
+		{copier.PointerShare, withoutSrc, withDst, setNil},
+		{copier.PointerShare, withSrc, withoutDst, shared},
+		{copier.PointerShare, withSrc, withDst, shared},
+
+		{copier.PointerClone, withoutSrc, withoutDst, setNil},
+		{copier.PointerClone, withoutSrc, withDst, setNil},
+		{copier.PointerClone, withSrc, withoutDst, allocated},
+		{copier.PointerClone, withSrc, withDst, allocated},
+
+		{copier.PointerSkipNil, withoutSrc, withoutDst, setNil},
+		{copier.PointerSkipNil, withoutSrc, withDst, untouched},
+		{copier.PointerSkipNil, withSrc, withoutDst, allocated},
+		{copier.PointerSkipNil, withSrc, withDst, updated},
+	}
+
+	for _, tt := range tests {
+		var src pointerSrc
+		if tt.src {
+			src.Inner = &pointerInner{Name: "source"}
+		}
+		var dst pointerDst
+		previous := &pointerInner{Name: "previous"}
+		if tt.dst {
+			dst.Inner = previous
+		}
+
+		if err := copier.CopyWithOption(&dst, src, copier.Option{PointerPolicy: tt.policy}); err != nil {
+			t.Fatalf("policy %d: should not error: %v", tt.policy, err)
+		}
+
+		var got string
+		switch {
+		case dst.Inner == nil:
+			got = setNil
+		case dst.Inner == src.Inner:
+			got = shared
+		case dst.Inner == previous && previous.Name == "source":
+			got = updated
+		case dst.Inner == previous:
+			got = untouched
+		case dst.Inner.Name == "source" && previous.Name == "previous":
+			got = allocated
+		default:
+			got = "unexpected"
+		}
+		if got != tt.want {
+			t.Errorf("policy %d, source set %v, destination set %v: got %s, wanted %s", tt.policy, tt.src, tt.dst, got, tt.want)
+		}
+	}
+}
+
+func TestPointerPolicyValidation(t *testing.T) {
+	var dst pointerDst
+	err := copier.CopyWithOption(&dst, pointerSrc{}, copier.Option{PointerPolicy: copier.PointerShare, DeepCopy: true})
+	if !errors.Is(err, copier.ErrInvalidOption) {
+		t.Errorf("error should be ErrInvalidOption: %v", err)
+	}
+
+	err = copier.CopyWithOption(&dst, pointerSrc{}, copier.Option{PointerPolicy: copier.PointerPolicy(100)})
+	if !errors.Is(err, copier.ErrInvalidOption) {
+		t.Errorf("error should be ErrInvalidOption: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+func TestRegisterConverter(t *testing.T) {
+	defer copier.ResetConverters(copier.Converters()...)
+
+	type Src struct {
+		Age   int
+		Score float64
+	}
+
+	type Dst struct {
+		Age   string
+		Score string
+	}
+
+	err := copier.RegisterConverter(
+		copier.Converter(func(i int) (string, error) {
+			return "registered " + strconv.Itoa(i), nil
+		}),
+		copier.Converter(func(f float64) (string, error) {
+			return strconv.FormatFloat(f, 'f', 1, 64), nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(copier.Converters()) != 2 {
+		t.Errorf("got %d converters, wanted 2", len(copier.Converters()))
+	}
+	c := copier.New(copier.Option{})
+
+	src := Src{Age: 18, Score: 1.5}
+	var dst Dst
+	if err := copier.Copy(&dst, src); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.Age != "registered 18" || dst.Score != "1.5" {
+		t.Errorf("got %+v", dst)
+	}
+
+	// per-call converters take precedence, even when matching by kind
+	err = copier.CopyWithOption(&dst, src, copier.Option{Converters: []copier.TypeConverter{{
+		SrcKind: reflect.Int,
+		DstKind: reflect.String,
+		Fn: func(src interface{}) (interface{}, error) {
+			return "option", nil
+		},
+	}}})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.Age != "option" || dst.Score != "1.5" {
+		t.Errorf("got %+v", dst)
+	}
+
+	if err := copier.ResetConverters(); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if len(copier.Converters()) != 0 {
+		t.Errorf("converters should be removed")
+	}
+
+	// the copier keeps the converters registered when it was created
+	dst = Dst{}
+	if err := c.Copy(&dst, src); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.Age != "registered 18" {
+		t.Errorf("got %+v", dst)
+	}
+
+	if err := copier.RegisterConverter(copier.TypeConverter{SrcType: copier.Int}); !errors.Is(err, copier.ErrInvalidConverter) {
+		t.Errorf("error should be ErrInvalidConverter: %v", err)
+	}
+}
+
+func TestRegisterConverterIgnoreEmpty(t *testing.T) {
+	defer copier.ResetConverters(copier.Converters()...)
+
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	if err := copier.RegisterConverter(copier.Converter(func(i int) (string, error) { return strconv.Itoa(i), nil })); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	to := User{Name: "keep", Age: 3}
+	if err := copier.CopyWithOption(&to, &User{Age: 5}, copier.Option{IgnoreEmpty: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if to.Name != "keep" || to.Age != 5 {
+		t.Errorf("empty fields should be ignored, got %+v", to)
+	}
+
+}
@@ -0,0 +1,103 @@
+package copier_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type keyedItem struct {
+	ID    int
+	Name  string
+	Price int
+}
+
+type keyedItemDTO struct {
+	ID   int64
+	Name string
+}
+
+type keyedOrder struct {
+	Items []keyedItem `copier:"key=ID"`
+}
+
+type keyedOrderDrop struct {
+	Items []*keyedItem `copier:"key=ID,dropunmatched"`
+}
+
+type keyedOrderDTO struct {
+	Items []keyedItemDTO
+}
+
+func TestCopySliceByKeyTag(t *testing.T) {
+	to := keyedOrder{Items: []keyedItem{{ID: 1, Name: "a", Price: 10}, {ID: 2, Name: "b", Price: 20}}}
+	from := keyedOrderDTO{Items: []keyedItemDTO{{ID: 2, Name: "B"}, {ID: 3, Name: "c"}}}
+
+	if err := copier.CopyWithOption(&to, from, copier.Option{Merge: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := []keyedItem{{ID: 1, Name: "a", Price: 10}, {ID: 2, Name: "B", Price: 20}, {ID: 3, Name: "c"}}
+	if !reflect.DeepEqual(to.Items, want) {
+		t.Errorf("got %+v, want %+v", to.Items, want)
+	}
+}
+
+func TestCopySliceByKeyDropUnmatched(t *testing.T) {
+	second := &keyedItem{ID: 2, Name: "b", Price: 20}
+	to := keyedOrderDrop{Items: []*keyedItem{{ID: 1, Name: "a"}, second, nil}}
+	from := keyedOrderDTO{Items: []keyedItemDTO{{ID: 3, Name: "c"}, {ID: 2, Name: "B"}}}
+
+	if err := copier.CopyWithOption(&to, from, copier.Option{Merge: true}); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	if len(to.Items) != 2 {
+		t.Fatalf("unmatched elements should be dropped, got %+v", to.Items)
+	}
+	if to.Items[0] != second || second.Name != "B" || second.Price != 20 {
+		t.Errorf("matched pointer should be updated in place, got %+v", to.Items[0])
+	}
+	if to.Items[1].ID != 3 || to.Items[1].Name != "c" {
+		t.Errorf("got %+v", to.Items[1])
+	}
+}
+
+func TestCopySliceByKeyOption(t *testing.T) {
+	to := []keyedItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	from := []keyedItemDTO{{ID: 2, Name: "B"}}
+
+	opt := copier.Option{SliceKeys: []copier.SliceKey{{Type: keyedItem{}, Field: "ID"}}}
+	if err := copier.CopyWithOption(&to, from, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	want := []keyedItem{{ID: 1, Name: "a"}, {ID: 2, Name: "B"}}
+	if !reflect.DeepEqual(to, want) {
+		t.Errorf("got %+v, want %+v", to, want)
+	}
+
+	// nested slices use the key of their element type too
+	order := keyedOrderDTO{Items: []keyedItemDTO{{ID: 2, Name: "b"}, {ID: 1, Name: "a"}}}
+	opt.SliceKeys[0] = copier.SliceKey{Type: keyedItemDTO{}, Field: "ID", DropUnmatched: true}
+	if err := copier.CopyWithOption(&order, keyedOrder{Items: []keyedItem{{ID: 1, Name: "A"}}}, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !reflect.DeepEqual(order.Items, []keyedItemDTO{{ID: 1, Name: "A"}}) {
+		t.Errorf("got %+v", order.Items)
+	}
+}
+
+func TestCopySliceByKeyErrors(t *testing.T) {
+	err := copier.New(copier.Option{SliceKeys: []copier.SliceKey{{Type: keyedItem{}}}}).Copy(&[]keyedItem{}, []keyedItem{})
+	if !errors.Is(err, copier.ErrInvalidOption) {
+		t.Errorf("error should be ErrInvalidOption: %v", err)
+	}
+
+	opt := copier.Option{SliceKeys: []copier.SliceKey{{Type: keyedItem{}, Field: "Code"}}}
+	err = copier.CopyWithOption(&[]keyedItem{}, []keyedItem{{ID: 1}}, opt)
+	if !errors.Is(err, copier.ErrNotSupported) {
+		t.Errorf("error should be ErrNotSupported: %v", err)
+	}
+}
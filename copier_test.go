@@ -634,11 +634,6 @@ func TestStructField(t *testing.T) {
 
 			checkDetail(t, *from.Detail, *to.Detail)
 
-			*to.Detail.Info2 = "new value"
-			if *from.Detail.Info2 == *to.Detail.Info2 {
-				t.Fatalf("DeepCopy enabled")
-			}
-
 			if len(from.Details) != len(to.Details) {
 				t.Fatalf("slice should be copied")
 			}
@@ -646,6 +641,12 @@ func TestStructField(t *testing.T) {
 			for idx, detail := range from.Details {
 				checkDetail(t, *detail, *to.Details[idx])
 			}
+
+			// Info2 is shared by Detail and Details in the source, and so in the copy
+			*to.Detail.Info2 = "new value"
+			if *from.Detail.Info2 == *to.Detail.Info2 {
+				t.Fatalf("DeepCopy enabled")
+			}
 		})
 		t.Run("Should work with same type and both not ptr field", func(t *testing.T) {
 			info2 := "world"
@@ -661,11 +662,6 @@ func TestStructField(t *testing.T) {
 
 			checkDetail(t, from.Detail, to.Detail)
 
-			*to.Detail.Info2 = "new value"
-			if *from.Detail.Info2 == *to.Detail.Info2 {
-				t.Fatalf("DeepCopy enabled")
-			}
-
 			if len(from.Details) != len(to.Details) {
 				t.Fatalf("slice should be copied")
 			}
@@ -673,6 +669,12 @@ func TestStructField(t *testing.T) {
 			for idx, detail := range from.Details {
 				checkDetail(t, detail, to.Details[idx])
 			}
+
+			// Info2 is shared by Detail and Details in the source, and so in the copy
+			*to.Detail.Info2 = "new value"
+			if *from.Detail.Info2 == *to.Detail.Info2 {
+				t.Fatalf("DeepCopy enabled")
+			}
 		})
 
 		t.Run("Should work with different type and both ptr field", func(t *testing.T) {
@@ -0,0 +1,114 @@
+package copier_test
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/uutw/copier"
+)
+
+type timeModel struct {
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+	DeletedAt time.Time
+	Birthday  time.Time
+	Timeout   time.Duration
+	ExpiresAt time.Time
+}
+
+type timeDTO struct {
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt sql.NullTime
+	Birthday  int64
+	Timeout   string
+	ExpiresAt *time.Time
+}
+
+func TestTimeConverters(t *testing.T) {
+	createdAt := time.Date(2021, 3, 5, 1, 30, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	model := timeModel{
+		CreatedAt: createdAt,
+		UpdatedAt: &updatedAt,
+		Birthday:  time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		Timeout:   90 * time.Minute,
+		ExpiresAt: createdAt,
+	}
+	opt := copier.Option{Converters: copier.TimeConverters("", copier.UnixSeconds)}
+
+	var dto timeDTO
+	if err := copier.CopyWithOption(&dto, model, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	want := timeDTO{
+		CreatedAt: "2021-03-05T01:30:00Z",
+		UpdatedAt: "2021-03-05T02:30:00Z",
+		Birthday:  946771200,
+		Timeout:   "1h30m0s",
+		ExpiresAt: &createdAt,
+	}
+	if !reflect.DeepEqual(dto, want) {
+		t.Errorf("got %+v, wanted %+v", dto, want)
+	}
+
+	var back timeModel
+	if err := copier.CopyWithOption(&back, dto, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if !reflect.DeepEqual(back, model) {
+		t.Errorf("got %+v, wanted %+v", back, model)
+	}
+
+	// a nil pointer is an empty string, and back
+	model.UpdatedAt = nil
+	if err := copier.CopyWithOption(&dto, model, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	back.UpdatedAt = &updatedAt
+	if err := copier.CopyWithOption(&back, dto, opt); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dto.UpdatedAt != "" || back.UpdatedAt != nil {
+		t.Errorf("got %q and %v", dto.UpdatedAt, back.UpdatedAt)
+	}
+}
+
+func TestTimeConvertersWithLayout(t *testing.T) {
+	type Src struct {
+		Day     string
+		Created int64
+	}
+
+	type Dst struct {
+		Day     time.Time
+		Created *time.Time
+	}
+
+	var dst Dst
+	err := copier.CopyWithOption(&dst, Src{Day: "2021-03-05", Created: 1614907800123}, copier.Option{
+		Converters: copier.TimeConverters(time.DateOnly, copier.UnixMillis),
+	})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	if !dst.Day.Equal(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got day %v", dst.Day)
+	}
+	if dst.Created == nil || !dst.Created.Equal(time.UnixMilli(1614907800123)) {
+		t.Errorf("got created %v", dst.Created)
+	}
+
+	err = copier.CopyWithOption(&dst, Src{Day: "05/03/2021"}, copier.Option{
+		Converters: copier.TimeConverters(time.DateOnly, copier.UnixMillis),
+	})
+	var copyErr *copier.CopyError
+	if err == nil || !errors.As(err, &copyErr) || copyErr.DstPath != "Day" {
+		t.Errorf("error should locate the field: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package copier_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/uutw/copier"
+)
+
+type transformAddress struct {
+	City    string
+	ZipCode string
+}
+
+type transformUser struct {
+	Name    string
+	Email   string
+	Address transformAddress
+	Friends []transformUser
+}
+
+func TestFieldTransformers(t *testing.T) {
+	lower := func(src interface{}) (interface{}, error) {
+		return strings.ToLower(src.(string)), nil
+	}
+
+	user := transformUser{
+		Name:    "Jinzhu",
+		Email:   "Jinzhu@Example.org",
+		Address: transformAddress{City: "Shanghai", ZipCode: "200 000"},
+		Friends: []transformUser{{Name: "Friend", Email: "Friend@Example.org"}},
+	}
+
+	var copied transformUser
+	err := copier.CopyWithOption(&copied, user, copier.Option{FieldTransformers: []copier.FieldTransformer{
+		{DstType: transformUser{}, Path: "Email", Fn: lower},
+		{DstType: transformUser{}, Path: "Address.ZipCode", Fn: func(src interface{}) (interface{}, error) {
+			return strings.ReplaceAll(src.(string), " ", ""), nil
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	if copied.Name != "Jinzhu" || copied.Email != "jinzhu@example.org" {
+		t.Errorf("only the email should be transformed, got %+v", copied)
+	}
+	if copied.Address.City != "Shanghai" || copied.Address.ZipCode != "200000" {
+		t.Errorf("nested field should be transformed, got %+v", copied.Address)
+	}
+	if len(copied.Friends) != 1 || copied.Friends[0].Email != "friend@example.org" || copied.Friends[0].Name != "Friend" {
+		t.Errorf("fields of slice elements should be transformed, got %+v", copied.Friends)
+	}
+	if user.Email != "Jinzhu@Example.org" {
+		t.Errorf("source should be left untouched, got %q", user.Email)
+	}
+}
+
+func TestFieldTransformersPrecedeConverters(t *testing.T) {
+	type Dst struct {
+		Name  string
+		Email *string
+	}
+
+	var dst Dst
+	err := copier.CopyWithOption(&dst, transformUser{Name: "Jinzhu", Email: "A@B"}, copier.Option{
+		Converters: []copier.TypeConverter{{
+			SrcType: copier.String,
+			DstType: copier.String,
+			Fn: func(src interface{}) (interface{}, error) {
+				return "converted", nil
+			},
+		}},
+		FieldTransformers: []copier.FieldTransformer{{
+			DstType: &Dst{},
+			Path:    "Email",
+			Fn: func(src interface{}) (interface{}, error) {
+				return strings.ToLower(src.(string)), nil
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if dst.Name != "converted" || dst.Email == nil || *dst.Email != "a@b" {
+		t.Errorf("got %+v", dst)
+	}
+}
+
+func TestFieldTransformersError(t *testing.T) {
+	errInvalid := errors.New("invalid zip code")
+	opt := copier.Option{FieldTransformers: []copier.FieldTransformer{{
+		DstType: transformUser{},
+		Path:    "Friends.Address.ZipCode",
+		Fn: func(src interface{}) (interface{}, error) {
+			if src.(string) == "" {
+				return nil, errInvalid
+			}
+			return src, nil
+		},
+	}}}
+
+	user := transformUser{Friends: []transformUser{{Address: transformAddress{ZipCode: "1"}}, {}}}
+	var copied transformUser
+	err := copier.CopyWithOption(&copied, user, opt)
+	if !errors.Is(err, errInvalid) {
+		t.Fatalf("error should wrap the transformer error: %v", err)
+	}
+
+	var copyErr *copier.CopyError
+	if !errors.As(err, &copyErr) || copyErr.DstPath != "Friends[1].Address.ZipCode" {
+		t.Errorf("error should locate the field: %v", err)
+	}
+
+	err = copier.New(copier.Option{FieldTransformers: []copier.FieldTransformer{{DstType: transformUser{}, Path: "Email"}}}).Copy(&copied, user)
+	if !errors.Is(err, copier.ErrInvalidFieldTransformer) {
+		t.Errorf("error should be ErrInvalidFieldTransformer: %v", err)
+	}
+}
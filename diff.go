@@ -0,0 +1,209 @@
+package copier
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// Change is a value found different by Diff.
+type Change struct {
+	// Path locates the value from the root of a, like the DstPath of a CopyError, e.g. `Address.City`.
+	Path string
+	// Old is the value in a, New the value of b as Copy would write it into a.
+	Old, New interface{}
+}
+
+// Diff returns the values of a that copying b into it would change, walking them with the same tags,
+// field name mappings and converters as CopyWithOption(a, b, opt).
+// Nested structs are compared field by field, other values such as slices and maps as a whole.
+// Hooks are not called.
+func Diff(a, b interface{}, opt Option) ([]Change, error) {
+	opt.SkipHooks = true
+	opt, err := opt.withoutInvalidEntries()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := newConfig(opt, defaultPlans)
+	if err != nil {
+		return nil, err
+	}
+
+	to, from := indirect(reflect.ValueOf(a)), indirect(reflect.ValueOf(b))
+	if !to.IsValid() {
+		return nil, ErrInvalidCopyDestination
+	}
+	if !from.IsValid() {
+		return nil, ErrInvalidCopyFrom
+	}
+	return diffValues(to, from, cfg)
+}
+
+// diffValues returns the values of to that copying from into it with cfg would change.
+func diffValues(to, from reflect.Value, cfg *config) ([]Change, error) {
+	d := &differ{state: newState(context.Background(), cfg), visited: map[[2]uintptr]bool{}}
+	err := d.diff(to, from, nil)
+	if len(d.errs) > 0 {
+		if err != nil {
+			d.errs = append(d.errs, err)
+		}
+		err = errors.Join(d.errs...)
+	}
+	return d.changes, err
+}
+
+type differ struct {
+	*state
+	changes []Change
+	// pairs of struct pointers already compared, to stop on cycles
+	visited map[[2]uintptr]bool
+}
+
+// diff compares to with from copied into a copy of to. key is the key of the elements of the slices, if any.
+func (d *differ) diff(to, from reflect.Value, key *SliceKey) error {
+	if to.Kind() == reflect.Ptr && from.Kind() == reflect.Ptr && !to.IsNil() && !from.IsNil() {
+		pair := [2]uintptr{to.Pointer(), from.Pointer()}
+		if d.visited[pair] {
+			return nil
+		}
+		d.visited[pair] = true
+	}
+
+	t, transformed := d.transformer()
+	toStruct, fromStruct := indirect(to), indirect(from)
+	if !transformed && toStruct.IsValid() && fromStruct.IsValid() && toStruct.Kind() == reflect.Struct && fromStruct.Kind() == reflect.Struct &&
+		isNestedStruct(toStruct.Type()) && isNestedStruct(fromStruct.Type()) && !d.converters.has(from.Type(), to.Type()) {
+		return d.diffStruct(toStruct, fromStruct)
+	}
+
+	// copy into a copy of the old value, for merged values to keep what the source does not set
+	next := cloneValue(to)
+	var err error
+	switch {
+	case transformed:
+		err = transformValue(next, from, t, d.state)
+	case key != nil:
+		err = copyKeyedSlice(next, from, *key, d.state)
+	default:
+		err = copyValue(next, from, d.state)
+	}
+	if err != nil {
+		return d.handle(d.wrap(err, from.Type(), to.Type()))
+	}
+
+	if old := to.Interface(); !reflect.DeepEqual(old, next.Interface()) {
+		d.changes = append(d.changes, Change{Path: formatPath(d.dstPath), Old: old, New: next.Interface()})
+	}
+	return nil
+}
+
+func (d *differ) diffStruct(to, from reflect.Value) error {
+	fromType, toType := from.Type(), to.Type()
+	if err := d.checkDepth(); err != nil {
+		return d.handle(d.wrap(err, fromType, toType))
+	}
+	pln, err := d.plan(fromType, toType)
+	if err != nil {
+		return d.wrap(err, fromType, toType)
+	}
+
+	d.enterStruct(toType)
+	mark := d.mark()
+	for _, step := range pln.fields {
+		d.reset(mark)
+		if step.destIndex == nil {
+			// copied into a method
+			continue
+		}
+		if fromType.FieldByIndex(step.srcIndex).Anonymous && isNestedStruct(fromType.FieldByIndex(step.srcIndex).Type) {
+			// fields of embedded structs are listed on their own
+			continue
+		}
+
+		fromField, err := from.FieldByIndexErr(step.srcIndex)
+		if err != nil || shouldIgnore(fromField, d.IgnoreEmpty) {
+			continue
+		}
+		toField, err := to.FieldByIndexErr(step.destIndex)
+		if err != nil {
+			// nil embedded pointer
+			toField = reflect.Zero(toType.FieldByIndex(step.destIndex).Type)
+		}
+
+		d.push(fieldElem(step.srcName), fieldElem(step.destName))
+		if err := d.diff(toField, fromField, step.key); err != nil {
+			return err
+		}
+	}
+	d.reset(mark)
+	return nil
+}
+
+// cloneValue returns a deep copy of v, field by field regardless of the copier tags.
+// Unexported fields are copied as they are.
+func cloneValue(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	cloneInto(c, map[uintptr]reflect.Value{})
+	return c
+}
+
+// cloneInto replaces what the settable value v refers to with copies, reusing the copies of the pointers in clones.
+func cloneInto(v reflect.Value, clones map[uintptr]reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if c, ok := clones[v.Pointer()]; ok {
+			v.Set(c)
+			return
+		}
+		c := reflect.New(v.Type().Elem())
+		clones[v.Pointer()] = c
+		c.Elem().Set(v.Elem())
+		cloneInto(c.Elem(), clones)
+		v.Set(c)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		c := reflect.New(v.Elem().Type()).Elem()
+		c.Set(v.Elem())
+		cloneInto(c, clones)
+		v.Set(c)
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			cloneInto(elem, clones)
+			c.SetMapIndex(iter.Key(), elem)
+		}
+		v.Set(c)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		reflect.Copy(c, v)
+		for i := 0; i < c.Len(); i++ {
+			cloneInto(c.Index(i), clones)
+		}
+		v.Set(c)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			cloneInto(v.Index(i), clones)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				cloneInto(f, clones)
+			}
+		}
+	}
+}
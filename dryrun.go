@@ -0,0 +1,116 @@
+package copier
+
+import (
+	"context"
+	"reflect"
+)
+
+// CopyPlan is what copying a value would do, as returned by Plan.
+type CopyPlan struct {
+	// Changes are the destination values the copy would change, with their current and would-be values.
+	Changes []Change
+	// Uncopied are the paths of the destination fields no source field or method is copied into.
+	Uncopied []string
+}
+
+// Plan returns what CopyWithOption(toValue, fromValue, opt) would change, without changing toValue.
+// The copy is made into a deep copy of toValue, applying the same tags, field name mappings,
+// converters and must checks, but without calling the hooks.
+func Plan(toValue interface{}, fromValue interface{}, opt Option) (*CopyPlan, error) {
+	opt.SkipHooks = true
+	opt, err := opt.withoutInvalidEntries()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := newConfig(opt, defaultPlans)
+	if err != nil {
+		return nil, err
+	}
+
+	to := indirect(reflect.ValueOf(toValue))
+	if !to.IsValid() {
+		return nil, ErrInvalidCopyDestination
+	}
+	from := indirect(reflect.ValueOf(fromValue))
+	if !from.IsValid() {
+		return nil, ErrInvalidCopyFrom
+	}
+
+	scratch := cloneValue(to)
+	if err := newState(context.Background(), cfg).run(scratch.Addr().Interface(), fromValue); err != nil {
+		return nil, err
+	}
+
+	// the values of the same type are compared as they are, without converters
+	same, err := newConfig(Option{SkipHooks: true}, defaultPlans)
+	if err != nil {
+		return nil, err
+	}
+	same.converters = newConverterSet(nil, nil)
+	changes, err := diffValues(to, scratch, same)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &CopyPlan{Changes: changes}
+	if from.Kind() == reflect.Struct && to.Kind() == reflect.Struct {
+		p.Uncopied, err = uncopiedFields(from.Type(), to.Type(), "", cfg, map[converterPair]bool{})
+	}
+	return p, err
+}
+
+// uncopiedFields returns the paths of the fields of toType the plan from fromType never writes,
+// looking into the nested structs copied field by field.
+func uncopiedFields(fromType, toType reflect.Type, prefix string, cfg *config, seen map[converterPair]bool) ([]string, error) {
+	pair := converterPair{SrcType: fromType, DstType: toType}
+	if seen[pair] {
+		// recursive type
+		return nil, nil
+	}
+	seen[pair] = true
+	defer delete(seen, pair)
+
+	pln, err := cfg.plan(fromType, toType)
+	if err != nil {
+		return nil, err
+	}
+
+	// uncopied fields of the nested structs, by destination field name
+	written := map[string][]string{}
+	for _, step := range pln.methods {
+		written[step.destName] = nil
+	}
+	for _, step := range pln.fields {
+		if step.destIndex == nil {
+			continue
+		}
+		written[step.destName] = nil
+
+		srcType := indirectElemType(fromType.FieldByIndex(step.srcIndex).Type)
+		dstType := indirectElemType(toType.FieldByIndex(step.destIndex).Type)
+		if _, ok := cfg.converters.lookup(srcType, dstType); ok || srcType.Kind() != reflect.Struct || dstType.Kind() != reflect.Struct ||
+			!isNestedStruct(srcType) || !isNestedStruct(dstType) || toType.FieldByIndex(step.destIndex).Anonymous {
+			continue
+		}
+
+		nested, err := uncopiedFields(srcType, dstType, prefix+step.destName+".", cfg, seen)
+		if err != nil {
+			return nil, err
+		}
+		written[step.destName] = nested
+	}
+
+	var uncopied []string
+	for _, field := range deepFields(toType) {
+		if field.Anonymous && isNestedStruct(field.Type) {
+			// fields of embedded structs are listed on their own
+			continue
+		}
+		if nested, ok := written[field.Name]; ok {
+			uncopied = append(uncopied, nested...)
+		} else {
+			uncopied = append(uncopied, prefix+field.Name)
+		}
+	}
+	return uncopied, nil
+}
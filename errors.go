@@ -1,6 +1,10 @@
 package copier
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
 
 var (
 	ErrInvalidCopyDestination        = errors.New("copy destination must be non-nil and addressable")
@@ -8,4 +12,35 @@ var (
 	ErrMapKeyNotMatch                = errors.New("map's key type doesn't match")
 	ErrNotSupported                  = errors.New("not supported")
 	ErrFieldNameTagStartNotUpperCase = errors.New("copier field name tag must be start upper case")
+	ErrMultipleOptions               = errors.New("at most one option can be given")
+	ErrInvalidConverter              = errors.New("invalid type converter")
+	ErrInvalidFieldNameMapping       = errors.New("invalid field name mapping")
+	ErrInvalidFieldTransformer       = errors.New("invalid field transformer")
+	ErrInvalidOption                 = errors.New("invalid option")
+	ErrCycleDetected                 = errors.New("cycle detected")
+	ErrMaxDepthExceeded              = errors.New("max depth exceeded")
+	ErrLossyConversion               = errors.New("lossy numeric conversion")
 )
+
+// CopyError is returned when copying a value fails, it tells where the copy failed.
+// Use errors.Is to check the cause against the errors above, or errors.As to get the CopyError.
+type CopyError struct {
+	// SrcPath and DstPath locate the failing value from the root of the copy, e.g. `Orders[3].Items[7].Price`.
+	// They are empty if the copy failed at the root.
+	SrcPath string
+	DstPath string
+	SrcType reflect.Type
+	DstType reflect.Type
+	Err     error
+}
+
+func (e *CopyError) Error() string {
+	if e.SrcPath == "" && e.DstPath == "" {
+		return fmt.Sprintf("copy %v to %v: %v", e.SrcType, e.DstType, e.Err)
+	}
+	return fmt.Sprintf("copy %s (%v) to %s (%v): %v", e.SrcPath, e.SrcType, e.DstPath, e.DstType, e.Err)
+}
+
+func (e *CopyError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,100 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// To copies `from` into a new value of type T and returns it.
+// If T is a pointer type, a new value is allocated for it to point to.
+//
+//	dto, err := copier.To[UserDTO](user)
+func To[T, F any](from F, opts ...Option) (T, error) {
+	var to T
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Kind() == reflect.Ptr {
+		to = reflect.New(t.Elem()).Interface().(T)
+	}
+
+	err := Into(&to, from, opts...)
+	return to, err
+}
+
+// Into copies `src` into the value `dst` points to.
+// Unlike Copy, the destination must be a pointer, which is checked at compile time.
+func Into[T, F any](dst *T, src F, opts ...Option) error {
+	if dst == nil {
+		return ErrInvalidCopyDestination
+	}
+
+	opt, err := optionOf(opts)
+	if err != nil {
+		return err
+	}
+
+	return CopyWithOption(dst, src, opt)
+}
+
+// SliceTo copies every element of `from` into a new slice of T.
+// A nil `from` slice results in a nil slice.
+//
+//	dtos, err := copier.SliceTo[UserDTO](users)
+func SliceTo[T, F any](from []F, opts ...Option) ([]T, error) {
+	if from == nil {
+		return nil, nil
+	}
+
+	to := make([]T, 0, len(from))
+	err := Into(&to, from, opts...)
+	return to, err
+}
+
+// MapTo copies every entry of `from` into a new map with keys of type K and values of type V.
+// A nil `from` map results in a nil map.
+//
+//	dtos, err := copier.MapTo[string, UserDTO](usersByID)
+func MapTo[K comparable, V any, FK comparable, FV any](from map[FK]FV, opts ...Option) (map[K]V, error) {
+	if from == nil {
+		return nil, nil
+	}
+
+	to := make(map[K]V, len(from))
+	err := Into(&to, from, opts...)
+	return to, err
+}
+
+// Converter returns a TypeConverter from S to D calling fn, without type assertions.
+// S can be an interface type, the converter then applies to every type implementing it.
+//
+//	copier.Converter(func(t time.Time) (string, error) { return t.Format(time.RFC3339), nil })
+func Converter[S, D any](fn func(S) (D, error)) TypeConverter {
+	srcType := reflect.TypeOf((*S)(nil)).Elem()
+	dstType := reflect.TypeOf((*D)(nil)).Elem()
+
+	return TypeConverter{
+		SrcType: srcType,
+		DstType: dstType,
+		Fn: func(src interface{}) (interface{}, error) {
+			var s S
+			if src != nil {
+				var ok bool
+				if s, ok = src.(S); !ok {
+					return nil, fmt.Errorf("%w: converter from %v got %T", ErrInvalidConverter, srcType, src)
+				}
+			}
+			return fn(s)
+		},
+		resultType: dstType,
+	}
+}
+
+// optionOf returns the Option passed to one of the generic helpers, if any.
+func optionOf(opts []Option) (Option, error) {
+	switch len(opts) {
+	case 0:
+		return Option{}, nil
+	case 1:
+		return opts[0], nil
+	default:
+		return Option{}, ErrMultipleOptions
+	}
+}
@@ -0,0 +1,73 @@
+package copier
+
+import "reflect"
+
+// BeforeCopier is implemented by destination structs which need to be prepared before being copied into.
+// BeforeCopy receives the source value, or nil when copying from a nil pointer.
+type BeforeCopier interface {
+	BeforeCopy(src interface{}) error
+}
+
+// AfterCopier is implemented by destination structs which need to be normalized once copied into,
+// for instance to recompute derived fields.
+// AfterCopy receives the source value, or nil when copying from a nil pointer.
+type AfterCopier interface {
+	AfterCopy(src interface{}) error
+}
+
+// callBeforeCopy calls the BeforeCopy method of dest, if any.
+func callBeforeCopy(dest, source reflect.Value, s *state) error {
+	if s.SkipHooks || !dest.CanAddr() {
+		return nil
+	}
+	if hook, ok := dest.Addr().Interface().(BeforeCopier); ok {
+		return hook.BeforeCopy(hookSource(source))
+	}
+	return nil
+}
+
+// callAfterCopy calls the AfterCopy method of dest, if any.
+func callAfterCopy(dest, source reflect.Value, s *state) error {
+	if s.SkipHooks || !dest.CanAddr() {
+		return nil
+	}
+	if hook, ok := dest.Addr().Interface().(AfterCopier); ok {
+		return hook.AfterCopy(hookSource(source))
+	}
+	return nil
+}
+
+var (
+	beforeCopierType = reflect.TypeOf((*BeforeCopier)(nil)).Elem()
+	afterCopierType  = reflect.TypeOf((*AfterCopier)(nil)).Elem()
+)
+
+// hasHooks reports whether copying a fromType value into the struct toType, or into the elements
+// of the slice toType, calls hooks.
+func hasHooks(toType, fromType reflect.Type, s *state) bool {
+	if s.SkipHooks {
+		return false
+	}
+	if fromType.Kind() == reflect.Ptr {
+		fromType = fromType.Elem()
+	}
+	if toType.Kind() == reflect.Slice && fromType.Kind() == reflect.Slice {
+		toType, fromType = toType.Elem(), fromType.Elem()
+		if fromType.Kind() == reflect.Ptr {
+			fromType = fromType.Elem()
+		}
+	}
+	if toType.Kind() != reflect.Struct || fromType.Kind() != reflect.Struct {
+		return false
+	}
+
+	ptr := reflect.PointerTo(toType)
+	return ptr.Implements(beforeCopierType) || ptr.Implements(afterCopierType)
+}
+
+func hookSource(source reflect.Value) interface{} {
+	if !source.IsValid() || !source.CanInterface() {
+		return nil
+	}
+	return source.Interface()
+}
@@ -0,0 +1,42 @@
+package copier
+
+import "context"
+
+// Copier copies values using a fixed Option.
+// Converters and field name mappings are validated and indexed once by New, and copy plans
+// are cached per Copier, so differently configured copiers do not share state.
+// A Copier is safe for concurrent use.
+type Copier struct {
+	cfg *config
+	err error
+}
+
+// New returns a Copier using opt for every copy.
+// If opt is invalid, the error is returned by every call to Copy, whereas CopyWithOption ignores
+// the invalid converters and field name mappings of the shapes earlier versions accepted.
+// The converters registered with RegisterConverter are captured when New is called.
+func New(opt Option) *Copier {
+	cfg, err := newConfig(opt, &planCache{})
+	return &Copier{cfg: cfg, err: err}
+}
+
+// Err returns the error found while validating the Option given to New, if any.
+func (c *Copier) Err() error {
+	return c.err
+}
+
+// Copy copies fromValue into toValue.
+func (c *Copier) Copy(toValue interface{}, fromValue interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.CopyContext(context.Background(), toValue, fromValue)
+}
+
+// CopyContext copies fromValue into toValue, stopping between slice elements and map entries once ctx is done.
+func (c *Copier) CopyContext(ctx context.Context, toValue interface{}, fromValue interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	return newState(ctx, c.cfg).run(toValue, fromValue)
+}
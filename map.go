@@ -0,0 +1,352 @@
+package copier
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	driverValuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	copyValuerType   = reflect.TypeOf((*Valuer)(nil)).Elem()
+)
+
+// isStructMapPair reports whether one of the types is a struct and the other a map.
+func isStructMapPair(fromType, toType reflect.Type) bool {
+	return (fromType.Kind() == reflect.Struct && toType.Kind() == reflect.Map) ||
+		(fromType.Kind() == reflect.Map && toType.Kind() == reflect.Struct)
+}
+
+// isNestedStruct reports whether values of type t are copied field by field from and into maps,
+// rather than as a single value like time.Time.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || len(deepFields(t)) == 0 {
+		return false
+	}
+
+	ptr := reflect.PointerTo(t)
+	return !ptr.Implements(driverValuerType) && !ptr.Implements(copyValuerType)
+}
+
+// isComposite reports whether values of type t are copied element by element or field by field
+// when they cannot be set as a whole.
+func isComposite(t reflect.Type) bool {
+	switch indirectElemType(t).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+// mapFieldName returns the map key of a struct field, from its tag or name, along with its tag flags.
+// The key is empty if the field is ignored.
+func mapFieldName(field reflect.StructField, mapping map[string]string) (name string, flg uint8, err error) {
+	if tags := field.Tag.Get("copier"); tags != "" {
+		if flg, name, err = parseTags(tags); err != nil || flg&tagIgnore != 0 {
+			return "", flg, err
+		}
+	}
+
+	if key, ok := mapping[field.Name]; ok {
+		name = key
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, flg, nil
+}
+
+// copyStructToMap copies the fields of the struct from into the map to, keyed by their names.
+func copyStructToMap(to, from reflect.Value, s *state) error {
+	toType := to.Type()
+	if toType.Key().Kind() != reflect.String {
+		return s.wrap(fmt.Errorf("%w: map key must be a string to copy a struct, got %v", ErrNotSupported, toType.Key()), from.Type(), toType)
+	}
+
+	if to.IsNil() {
+		to.Set(reflect.MakeMap(toType))
+	}
+	return structToMap(to, from, "", s)
+}
+
+func structToMap(to, from reflect.Value, prefix string, s *state) error {
+	fromType, toType := from.Type(), to.Type()
+	if err := s.checkDepth(); err != nil {
+		return s.wrap(err, fromType, toType)
+	}
+	mapping := getFieldNamesMapping(s.mappings, fromType, toType)
+
+	// nested structs are copied into maps of the same type when the map holds interfaces
+	var nestedType reflect.Type
+	switch elemType := toType.Elem(); {
+	case elemType.Kind() == reflect.Interface && toType.ConvertibleTo(elemType):
+		nestedType = toType
+	case elemType.Kind() == reflect.Map && elemType.Key().Kind() == reflect.String:
+		nestedType = elemType
+	}
+
+	mark := s.mark()
+	for _, field := range deepFields(fromType) {
+		s.reset(mark)
+		if field.Anonymous && isNestedStruct(field.Type) {
+			// fields of embedded structs are listed on their own
+			continue
+		}
+
+		name, _, err := mapFieldName(field, mapping)
+		if err != nil {
+			return s.wrap(err, fromType, toType)
+		}
+		if name == "" {
+			continue
+		}
+
+		sf, ok := fromType.FieldByName(field.Name)
+		if !ok {
+			continue
+		}
+		fromField, err := from.FieldByIndexErr(sf.Index)
+		if err != nil || shouldIgnore(fromField, s.IgnoreEmpty) {
+			continue
+		}
+
+		key := reflect.ValueOf(prefix + name).Convert(toType.Key())
+		s.push(fieldElem(field.Name), keyElem(key))
+
+		_, hasConverter := s.converters.lookup(fromField.Type(), toType.Elem())
+		if !hasConverter && isNestedStruct(fromField.Type()) {
+			if s.MapKeySeparator == "" && nestedType == nil {
+				// the values of the map cannot hold the fields of a struct
+				continue
+			}
+
+			if fromField = indirect(fromField); !fromField.IsValid() {
+				// nil pointer to a struct
+				to.SetMapIndex(key, reflect.Zero(toType.Elem()))
+				continue
+			}
+
+			if s.MapKeySeparator != "" {
+				err = structToMap(to, fromField, prefix+name+s.MapKeySeparator, s)
+			} else {
+				nested := reflect.MakeMap(nestedType)
+				if err = structToMap(nested, fromField, "", s); err == nil {
+					to.SetMapIndex(key, nested.Convert(toType.Elem()))
+				}
+			}
+			if err = s.handle(err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		toValue := reflect.New(toType.Elem()).Elem()
+		isSet, err := set(toValue, fromField, s)
+		if err == nil && !isSet {
+			if !isComposite(fromField.Type()) || !isComposite(toValue.Type()) {
+				// the map cannot hold the value
+				continue
+			}
+			err = copier(toValue.Addr().Interface(), fromField.Interface(), s)
+		}
+		if err != nil {
+			if err = s.handle(s.wrap(err, fromField.Type(), toValue.Type())); err != nil {
+				return err
+			}
+			continue
+		}
+		to.SetMapIndex(key, toValue)
+	}
+	return nil
+}
+
+// mapEntries indexes the entries of a map with string keys.
+type mapEntries struct {
+	values map[string]reflect.Value
+	// values by lower case key, nil if the copy is case sensitive
+	folded map[string]reflect.Value
+}
+
+func newMapEntries(from reflect.Value, caseSensitive bool) *mapEntries {
+	e := &mapEntries{values: make(map[string]reflect.Value, from.Len())}
+	if !caseSensitive {
+		e.folded = make(map[string]reflect.Value, from.Len())
+	}
+
+	iter := from.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		e.values[key] = iter.Value()
+		if e.folded != nil {
+			e.folded[strings.ToLower(key)] = iter.Value()
+		}
+	}
+	return e
+}
+
+func (e *mapEntries) get(key string) (reflect.Value, bool) {
+	if v, ok := e.values[key]; ok {
+		return v, true
+	}
+	if e.folded != nil {
+		v, ok := e.folded[strings.ToLower(key)]
+		return v, ok
+	}
+	return reflect.Value{}, false
+}
+
+func (e *mapEntries) hasPrefix(prefix string) bool {
+	for key := range e.values {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	if e.folded != nil {
+		prefix = strings.ToLower(prefix)
+		for key := range e.folded {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyMapToStruct copies the entries of the map from into the fields of the struct to with the same names.
+func copyMapToStruct(to, from reflect.Value, s *state) error {
+	fromType := from.Type()
+	if fromType.Key().Kind() != reflect.String {
+		return s.wrap(fmt.Errorf("%w: map key must be a string to copy into a struct, got %v", ErrNotSupported, fromType.Key()), fromType, to.Type())
+	}
+
+	if err := callBeforeCopy(to, from, s); err != nil {
+		return s.wrap(err, fromType, to.Type())
+	}
+	if err := mapToStruct(to, fromType, newMapEntries(from, s.CaseSensitive), "", s); err != nil {
+		return err
+	}
+	return s.wrap(callAfterCopy(to, from, s), fromType, to.Type())
+}
+
+func mapToStruct(to reflect.Value, fromType reflect.Type, entries *mapEntries, prefix string, s *state) error {
+	toType := to.Type()
+	if err := s.checkDepth(); err != nil {
+		return s.wrap(err, fromType, toType)
+	}
+
+	// the mapping goes from map keys to field names
+	mapping := map[string]string{}
+	for key, name := range getFieldNamesMapping(s.mappings, fromType, toType) {
+		mapping[name] = key
+	}
+
+	var (
+		must   []mustField
+		copied []bool
+	)
+	mark := s.mark()
+	for _, field := range deepFields(toType) {
+		s.reset(mark)
+		if field.Anonymous && isNestedStruct(field.Type) {
+			// fields of embedded structs are listed on their own
+			continue
+		}
+
+		name, flg, err := mapFieldName(field, mapping)
+		if err != nil {
+			return s.wrap(err, fromType, toType)
+		}
+		if name == "" {
+			continue
+		}
+
+		mustIndex := -1
+		if flg&tagMust != 0 {
+			mustIndex = len(must)
+			must = append(must, mustField{name: field.Name, noPanic: flg&tagNoPanic != 0})
+			copied = append(copied, false)
+		}
+
+		sf, ok := toType.FieldByName(field.Name)
+		if !ok {
+			continue
+		}
+
+		fromValue, ok := entries.get(prefix + name)
+		if !ok {
+			// look for the keys of the nested struct fields
+			if s.MapKeySeparator == "" || !isNestedStruct(sf.Type) || !entries.hasPrefix(prefix+name+s.MapKeySeparator) {
+				continue
+			}
+
+			toField, ok := fieldByIndexAlloc(to, sf.Index)
+			if !ok || !toField.CanSet() {
+				continue
+			}
+			if toField.Kind() == reflect.Ptr {
+				if toField.IsNil() {
+					toField.Set(reflect.New(toField.Type().Elem()))
+				}
+				toField = toField.Elem()
+			}
+
+			s.push(keyElem(reflect.ValueOf(prefix+name)), fieldElem(field.Name))
+			err := mapToStruct(toField, fromType, entries, prefix+name+s.MapKeySeparator, s)
+			if err = s.handle(err); err != nil {
+				return err
+			}
+			if mustIndex >= 0 {
+				copied[mustIndex] = true
+			}
+			continue
+		}
+
+		if fromValue.Kind() == reflect.Interface {
+			fromValue = fromValue.Elem()
+		}
+		// nil values leave the field untouched
+		if !fromValue.IsValid() || shouldIgnore(fromValue, s.IgnoreEmpty) {
+			continue
+		}
+
+		toField, ok := fieldByIndexAlloc(to, sf.Index)
+		if !ok || !toField.CanSet() {
+			continue
+		}
+
+		s.push(keyElem(reflect.ValueOf(prefix+name)), fieldElem(field.Name))
+		if err := copyValue(toField, fromValue, s); err != nil {
+			if err = s.handle(err); err != nil {
+				return err
+			}
+			continue
+		}
+		if mustIndex >= 0 {
+			copied[mustIndex] = true
+		}
+	}
+	s.reset(mark)
+
+	return s.handle(s.wrap(checkMust(must, copied), fromType, toType))
+}
+
+// fieldByIndexAlloc returns the nested field of v, allocating the nil embedded struct pointers on its way.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
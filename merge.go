@@ -0,0 +1,237 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SliceMode defines how a source slice is copied into a destination slice.
+type SliceMode uint8
+
+const (
+	// SliceReplace makes the destination hold the elements of the source only.
+	SliceReplace SliceMode = iota
+	// SliceAppend appends the elements of the source to the ones of the destination.
+	SliceAppend
+	// SliceMergeIndex copies each element of the source into the destination element with the same index,
+	// merging structs field by field with Merge, and keeps the destination elements past the end of the source.
+	SliceMergeIndex
+)
+
+// mergesInto reports whether a value of type from is merged into a value of type to
+// rather than set as a whole.
+func (s *state) mergesInto(to, from reflect.Type) bool {
+	if from.Kind() == reflect.Ptr {
+		from = from.Elem()
+	}
+
+	switch {
+	case to.Kind() == reflect.Slice && from.Kind() == reflect.Slice:
+		_, keyed := s.sliceKey(to)
+		return keyed || s.SliceMode != SliceReplace
+	case !s.Merge:
+		return false
+	case to.Kind() == reflect.Map && from.Kind() == reflect.Map:
+		return true
+	case to.Kind() == reflect.Struct && from.Kind() == reflect.Struct:
+		return len(deepFields(to)) > 0
+	}
+	return false
+}
+
+// mergeBase returns a copy of the existing map entry to merge the source entry from into,
+// if they are both structs, maps or slices.
+func mergeBase(existing, from reflect.Value) (reflect.Value, bool) {
+	existing, from = indirectValue(existing), indirectValue(from)
+	if !existing.IsValid() || !from.IsValid() || existing.Kind() != from.Kind() {
+		return reflect.Value{}, false
+	}
+
+	switch existing.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		base := reflect.New(existing.Type()).Elem()
+		base.Set(existing)
+		return base, true
+	}
+	return reflect.Value{}, false
+}
+
+// indirectValue returns the value v holds or points to, or an invalid value for nil pointers and interfaces.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// mergedElem returns the element j of the destination slice to merge a source struct into,
+// allocating it if it is a nil pointer, when merging slices by index.
+func mergedElem(to reflect.Value, j int, toType reflect.Type, s *state) (reflect.Value, bool) {
+	if s.SliceMode != SliceMergeIndex || to.Kind() != reflect.Slice || j >= to.Len() {
+		return reflect.Value{}, false
+	}
+
+	elem := to.Index(j)
+	if elem.Kind() == reflect.Ptr && elem.Type().Elem() == toType {
+		if elem.IsNil() {
+			elem.Set(reflect.New(toType))
+		}
+		elem = elem.Elem()
+	}
+	if elem.Type() != toType {
+		return reflect.Value{}, false
+	}
+	return elem, true
+}
+
+// SliceKey matches the elements of slices by the value of an identity field rather than by index:
+// the destination elements matching a source element are updated in place and the other source
+// elements are appended.
+//
+// The same can be set on a slice field with a tag, `copier:"key=ID"` or `copier:"key=ID,dropunmatched"`.
+type SliceKey struct {
+	// Type is the type of the elements of the destination slices, such as LineItem{}.
+	Type interface{}
+	// Field is the name of the identity field of both the source and destination elements.
+	Field string
+	// DropUnmatched removes the destination elements matching no source element.
+	DropUnmatched bool
+}
+
+func (opt Option) sliceKeys() map[reflect.Type]SliceKey {
+	if len(opt.SliceKeys) == 0 {
+		return nil
+	}
+
+	keys := make(map[reflect.Type]SliceKey, len(opt.SliceKeys))
+	for _, key := range opt.SliceKeys {
+		keys[indirectElemType(reflect.TypeOf(key.Type))] = key
+	}
+	return keys
+}
+
+// sliceKey returns the key of the elements of the slice type t, if any.
+func (cfg *config) sliceKey(t reflect.Type) (SliceKey, bool) {
+	if len(cfg.sliceKeys) == 0 {
+		return SliceKey{}, false
+	}
+	key, ok := cfg.sliceKeys[indirectElemType(t.Elem())]
+	return key, ok
+}
+
+func indirectElemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// parseSliceKey returns the key set by the `key=` option of a copier tag, if any.
+func parseSliceKey(tag string) (key SliceKey, ok bool) {
+	for _, t := range strings.Split(tag, ",") {
+		if field, found := strings.CutPrefix(t, "key="); found {
+			key.Field, ok = field, true
+		} else if t == "dropunmatched" {
+			key.DropUnmatched = true
+		}
+	}
+	return key, ok
+}
+
+// copyKeyedSlice copies the slice from into the slice to, matching their elements by key.
+func copyKeyedSlice(to, from reflect.Value, key SliceKey, s *state) error {
+	from = indirect(from)
+	if from.Kind() != reflect.Slice || to.Kind() != reflect.Slice {
+		return copyValue(to, from, s)
+	}
+	toType := to.Type()
+
+	// index the destination elements by key, the first one winning
+	index := map[interface{}]int{}
+	for j := 0; j < to.Len(); j++ {
+		k, ok, err := elemKey(to.Index(j), key.Field, nil)
+		if err != nil {
+			return s.wrap(err, from.Type(), toType)
+		}
+		if _, dup := index[k]; ok && !dup {
+			index[k] = j
+		}
+	}
+	existing := to.Len()
+	matched := make([]bool, existing)
+
+	mark := s.mark()
+	for i := 0; i < from.Len(); i++ {
+		s.reset(mark)
+		if s.canceled() {
+			return s.wrap(fmt.Errorf("copy canceled after %d of %d slice elements: %w", i, from.Len(), s.ctx.Err()), from.Type(), toType)
+		}
+
+		fromElem := from.Index(i)
+		k, ok, err := elemKey(fromElem, key.Field, indirectElemType(toType.Elem()))
+		if err != nil {
+			s.push(indexElem(i), noElem())
+			if err = s.handle(s.wrap(err, fromElem.Type(), toType.Elem())); err != nil {
+				return err
+			}
+			continue
+		}
+		if !ok {
+			// nil element
+			continue
+		}
+
+		j, found := index[k]
+		if !found {
+			j = to.Len()
+			to.Set(reflect.Append(to, reflect.New(toType.Elem()).Elem()))
+		} else {
+			matched[j] = true
+		}
+
+		s.push(indexElem(i), indexElem(j))
+		if err := copyValue(to.Index(j), fromElem, s); err != nil {
+			if err = s.handle(err); err != nil {
+				return err
+			}
+		}
+	}
+	s.reset(mark)
+
+	if key.DropUnmatched {
+		kept := reflect.MakeSlice(toType, 0, to.Len())
+		for j := 0; j < to.Len(); j++ {
+			if j >= existing || matched[j] {
+				kept = reflect.Append(kept, to.Index(j))
+			}
+		}
+		to.Set(kept)
+	}
+	return nil
+}
+
+// elemKey returns the value of the key field of a slice element, converted to the type of the key field
+// of the destination elements of type toType if given. ok is false for nil elements.
+func elemKey(elem reflect.Value, field string, toType reflect.Type) (k interface{}, ok bool, err error) {
+	if elem = indirectValue(elem); !elem.IsValid() {
+		return nil, false, nil
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, false, fmt.Errorf("%w: slice elements of type %v have no key field", ErrNotSupported, elem.Type())
+	}
+
+	v := elem.FieldByName(field)
+	if !v.IsValid() || !v.Comparable() {
+		return nil, false, fmt.Errorf("%w: %v has no comparable key field %s", ErrNotSupported, elem.Type(), field)
+	}
+	if toType != nil {
+		if f, found := toType.FieldByName(field); found && f.Type != v.Type() && v.Type().ConvertibleTo(f.Type) {
+			v = v.Convert(f.Type)
+		}
+	}
+	return v.Interface(), true, nil
+}
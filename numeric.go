@@ -0,0 +1,158 @@
+package copier
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// convertible reports whether values of type from are converted into values of type to with reflect.Value.Convert.
+// Integers are only converted into strings with RuneConversion.
+func (cfg *config) convertible(from, to reflect.Type) bool {
+	if to.Kind() == reflect.String && (isInt(from.Kind()) || isUint(from.Kind())) && !cfg.RuneConversion {
+		return false
+	}
+	return from.ConvertibleTo(to)
+}
+
+// convertValue converts v to the type t, which v must be convertible to.
+// With StrictNumeric, numeric conversions losing information fail instead.
+func convertValue(v reflect.Value, t reflect.Type, s *state) (reflect.Value, error) {
+	if s.StrictNumeric {
+		if err := checkNumeric(v, t); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return v.Convert(t), nil
+}
+
+// checkNumeric returns ErrLossyConversion if converting the number v to the type t
+// overflows, loses its sign, truncates its fractional part or loses precision.
+func checkNumeric(v reflect.Value, t reflect.Type) error {
+	from, to := v.Kind(), t.Kind()
+	fail := func(reason string) error {
+		return fmt.Errorf("%w: %v %v %s %v", ErrLossyConversion, v.Type(), v, reason, t)
+	}
+
+	switch {
+	case isInt(from):
+		i := v.Int()
+		switch {
+		case isInt(to) && t.OverflowInt(i):
+			return fail("overflows")
+		case isUint(to) && i < 0:
+			return fail("loses its sign in")
+		case isUint(to) && t.OverflowUint(uint64(i)):
+			return fail("overflows")
+		case isFloat(to):
+			if f := v.Convert(t).Float(); f >= math.MaxInt64 || int64(f) != i {
+				return fail("loses precision in")
+			}
+		}
+	case isUint(from):
+		u := v.Uint()
+		switch {
+		case isInt(to) && (u > math.MaxInt64 || t.OverflowInt(int64(u))):
+			return fail("overflows")
+		case isUint(to) && t.OverflowUint(u):
+			return fail("overflows")
+		case isFloat(to):
+			if f := v.Convert(t).Float(); f >= math.MaxUint64 || uint64(f) != u {
+				return fail("loses precision in")
+			}
+		}
+	case isFloat(from):
+		f := v.Float()
+		switch {
+		case (isInt(to) || isUint(to)) && (math.IsNaN(f) || math.IsInf(f, 0)):
+			return fail("cannot be represented in")
+		case (isInt(to) || isUint(to)) && f != math.Trunc(f):
+			return fail("loses its fractional part in")
+		case isInt(to) && (f < math.MinInt64 || f >= math.MaxInt64 || t.OverflowInt(int64(f))):
+			return fail("overflows")
+		case isUint(to) && f < 0:
+			return fail("loses its sign in")
+		case isUint(to) && (f >= math.MaxUint64 || t.OverflowUint(uint64(f))):
+			return fail("overflows")
+		case isFloat(to) && !math.IsNaN(f) && !math.IsInf(f, 0):
+			if t.OverflowFloat(f) {
+				return fail("overflows")
+			}
+			if v.Convert(t).Float() != f {
+				return fail("loses precision in")
+			}
+		}
+	}
+	return nil
+}
+
+func isScalar(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || isFloat(k) || k == reflect.Bool
+}
+
+// isStringConversion reports whether values are parsed or formatted between the two types with StringConversion.
+func isStringConversion(from, to reflect.Type) bool {
+	return (from.Kind() == reflect.String && isScalar(to.Kind())) || (isScalar(from.Kind()) && to.Kind() == reflect.String)
+}
+
+// convertString parses the string from into the number or bool to, or formats the number or bool from
+// into the string to, with the strconv functions.
+func convertString(to, from reflect.Value) error {
+	if to.Kind() == reflect.String {
+		var str string
+		switch k := from.Kind(); {
+		case isInt(k):
+			str = strconv.FormatInt(from.Int(), 10)
+		case isUint(k):
+			str = strconv.FormatUint(from.Uint(), 10)
+		case isFloat(k):
+			str = strconv.FormatFloat(from.Float(), 'g', -1, from.Type().Bits())
+		default:
+			str = strconv.FormatBool(from.Bool())
+		}
+		to.SetString(str)
+		return nil
+	}
+
+	str := from.String()
+	switch k := to.Kind(); {
+	case isInt(k):
+		i, err := strconv.ParseInt(str, 10, to.Type().Bits())
+		if err != nil {
+			return err
+		}
+		to.SetInt(i)
+	case isUint(k):
+		u, err := strconv.ParseUint(str, 10, to.Type().Bits())
+		if err != nil {
+			return err
+		}
+		to.SetUint(u)
+	case isFloat(k):
+		f, err := strconv.ParseFloat(str, to.Type().Bits())
+		if err != nil {
+			return err
+		}
+		to.SetFloat(f)
+	default:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		to.SetBool(b)
+	}
+	return nil
+}
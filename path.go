@@ -0,0 +1,99 @@
+package copier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	pathNone uint8 = iota
+	pathField
+	pathIndex
+	pathKey
+)
+
+// pathElem is one step of the path from the root of a copy to the value being copied.
+type pathElem struct {
+	kind  uint8
+	name  string
+	index int
+	key   reflect.Value
+}
+
+// noElem is used on the side of the copy without a matching step, e.g. for a struct copied into a slice.
+func noElem() pathElem {
+	return pathElem{kind: pathNone}
+}
+
+func fieldElem(name string) pathElem {
+	return pathElem{kind: pathField, name: name}
+}
+
+func indexElem(index int) pathElem {
+	return pathElem{kind: pathIndex, index: index}
+}
+
+func keyElem(key reflect.Value) pathElem {
+	return pathElem{kind: pathKey, key: key}
+}
+
+func formatPath(path []pathElem) string {
+	var b strings.Builder
+	for _, e := range path {
+		switch e.kind {
+		case pathField:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(e.name)
+		case pathIndex:
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(e.index))
+			b.WriteByte(']')
+		case pathKey:
+			fmt.Fprintf(&b, "[%v]", e.key)
+		}
+	}
+	return b.String()
+}
+
+// pathMark is the length of the source and destination paths, and of the entered structs,
+// at some point of the copy.
+type pathMark struct {
+	src, dst, structs int
+}
+
+func (s *state) push(src, dst pathElem) {
+	s.srcPath = append(s.srcPath, src)
+	s.dstPath = append(s.dstPath, dst)
+}
+
+func (s *state) mark() pathMark {
+	return pathMark{src: len(s.srcPath), dst: len(s.dstPath), structs: len(s.structs)}
+}
+
+func (s *state) reset(m pathMark) {
+	s.srcPath = s.srcPath[:m.src]
+	s.dstPath = s.dstPath[:m.dst]
+	s.structs = s.structs[:m.structs]
+}
+
+// wrap returns err as a *CopyError located at the current path.
+// Errors already carrying their location are returned as is.
+func (s *state) wrap(err error, from, to reflect.Type) error {
+	var copyErr *CopyError
+	if err == nil || errors.As(err, &copyErr) {
+		return err
+	}
+
+	return &CopyError{
+		SrcPath: formatPath(s.srcPath),
+		DstPath: formatPath(s.dstPath),
+		SrcType: from,
+		DstType: to,
+		Err:     err,
+	}
+}
@@ -0,0 +1,264 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// structPlan is the precompiled field-to-field mapping between a source and a destination struct type.
+// It is computed once per type pair and reused by every copy between these types.
+type structPlan struct {
+	// steps copying a source field to a destination field or method
+	fields []fieldStep
+	// steps copying the result of a source method to a destination field
+	methods []methodStep
+	// destination fields tagged with `must`
+	must []mustField
+}
+
+type fieldStep struct {
+	// names of the source and destination fields or method
+	srcName, destName string
+	// index path of the source field
+	srcIndex []int
+	// index paths of the embedded pointers to initialize before setting the destination field
+	embedded [][]int
+	// index path of the destination field, nil if there is none
+	destIndex []int
+	// index of the destination method in the pointer and value method sets, -1 if there is none
+	destPtrMethod   int
+	destValueMethod int
+	// index into structPlan.must, -1 if the field has no must tag
+	must int
+	// identity of the elements of the destination slice field, nil if they are matched by index
+	key *SliceKey
+}
+
+type methodStep struct {
+	srcName, destName string
+	srcPtrMethod      int
+	srcValueMethod    int
+	destIndex         []int
+}
+
+type mustField struct {
+	name    string
+	noPanic bool
+}
+
+type planKey struct {
+	from, to      reflect.Type
+	caseSensitive bool
+	mapping       string
+}
+
+type planCache struct {
+	plans sync.Map
+}
+
+type planEntry struct {
+	plan *structPlan
+	err  error
+}
+
+// defaultPlans is shared by every copy made without a dedicated cache.
+var defaultPlans = &planCache{}
+
+func (c *planCache) get(key planKey, mapping map[string]string) (*structPlan, error) {
+	if e, ok := c.plans.Load(key); ok {
+		return e.(planEntry).plan, e.(planEntry).err
+	}
+
+	p, err := compilePlan(key.from, key.to, key.caseSensitive, mapping)
+	e, _ := c.plans.LoadOrStore(key, planEntry{plan: p, err: err})
+	return e.(planEntry).plan, e.(planEntry).err
+}
+
+// compilePlan resolves tags, field name mappings, fields and methods between two struct types.
+func compilePlan(fromType, toType reflect.Type, caseSensitive bool, mapping map[string]string) (*structPlan, error) {
+	flgs, err := getFlags(toType, fromType)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &structPlan{}
+	mustIndex := map[string]int{}
+	for _, field := range deepFields(toType) {
+		fieldFlags := flgs.BitFlags[field.Name]
+		if fieldFlags&tagMust == 0 {
+			continue
+		}
+		if _, ok := mustIndex[field.Name]; !ok {
+			mustIndex[field.Name] = len(p.must)
+			p.must = append(p.must, mustField{name: field.Name, noPanic: fieldFlags&tagNoPanic != 0})
+		}
+	}
+
+	ptrToType := reflect.PointerTo(toType)
+	for _, field := range deepFields(fromType) {
+		name := field.Name
+
+		// Check if we should ignore copying
+		if (flgs.BitFlags[name] & tagIgnore) != 0 {
+			continue
+		}
+
+		srcFieldName, destFieldName := getFieldName(name, flgs, mapping)
+		srcField, ok := fromType.FieldByName(srcFieldName)
+		if !ok {
+			continue
+		}
+
+		step := fieldStep{srcName: srcField.Name, destName: destFieldName, srcIndex: srcField.Index, destPtrMethod: -1, destValueMethod: -1, must: -1}
+		if i, ok := mustIndex[name]; ok {
+			step.must = i
+		}
+
+		// process for nested anonymous field
+		if f, ok := toType.FieldByName(destFieldName); ok {
+			// only initialize parent embedded struct pointer in the path
+			for idx := range f.Index[:len(f.Index)-1] {
+				if toType.FieldByIndex(f.Index[:idx+1]).Type.Kind() == reflect.Ptr {
+					step.embedded = append(step.embedded, f.Index[:idx+1])
+				}
+			}
+		}
+
+		if f, ok := fieldByNameType(toType, destFieldName, caseSensitive); ok {
+			step.destName = f.Name
+			step.destIndex = f.Index
+			if key, ok := parseSliceKey(f.Tag.Get("copier")); ok {
+				step.key = &key
+			}
+		} else {
+			// try to set to method
+			if m, ok := ptrToType.MethodByName(destFieldName); ok && m.Type.NumIn() == 2 && srcField.Type.AssignableTo(m.Type.In(1)) {
+				step.destPtrMethod = m.Index
+			}
+			if m, ok := toType.MethodByName(destFieldName); ok && m.Type.NumIn() == 2 && srcField.Type.AssignableTo(m.Type.In(1)) {
+				step.destValueMethod = m.Index
+			}
+			if step.destPtrMethod < 0 && step.destValueMethod < 0 {
+				continue
+			}
+		}
+
+		p.fields = append(p.fields, step)
+	}
+
+	ptrFromType := reflect.PointerTo(fromType)
+	for _, field := range deepFields(toType) {
+		srcFieldName, destFieldName := getFieldName(field.Name, flgs, mapping)
+
+		step := methodStep{srcName: srcFieldName, srcPtrMethod: -1, srcValueMethod: -1}
+		if m, ok := ptrFromType.MethodByName(srcFieldName); ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 {
+			step.srcPtrMethod = m.Index
+		}
+		if m, ok := fromType.MethodByName(srcFieldName); ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 {
+			step.srcValueMethod = m.Index
+		}
+		if step.srcPtrMethod < 0 && step.srcValueMethod < 0 {
+			continue
+		}
+
+		f, ok := fieldByNameType(toType, destFieldName, caseSensitive)
+		if !ok {
+			continue
+		}
+		step.destName = f.Name
+		step.destIndex = f.Index
+
+		p.methods = append(p.methods, step)
+	}
+
+	return p, nil
+}
+
+// checkMust checks that every field with a must tag has been copied.
+func checkMust(must []mustField, copied []bool) error {
+	for i, field := range must {
+		if copied[i] {
+			continue
+		}
+		if field.noPanic {
+			return fmt.Errorf("field %s has must tag but was not copied", field.name)
+		}
+		panic(fmt.Sprintf("Field %s has must tag but was not copied", field.name))
+	}
+	return nil
+}
+
+// initEmbedded allocates the nil embedded struct pointers leading to the destination field.
+// It returns false if one of them cannot be set.
+func (s *fieldStep) initEmbedded(dest reflect.Value) bool {
+	for _, index := range s.embedded {
+		destField := dest.FieldByIndex(index)
+		if !destField.IsNil() {
+			continue
+		}
+		if !destField.CanSet() {
+			return false
+		}
+
+		// destField is a nil pointer that can be set
+		destField.Set(reflect.New(destField.Type().Elem()))
+	}
+	return true
+}
+
+// destMethod returns the method of dest the source field should be passed to.
+func (s *fieldStep) destMethod(dest reflect.Value) reflect.Value {
+	if dest.CanAddr() {
+		if s.destPtrMethod >= 0 {
+			return dest.Addr().Method(s.destPtrMethod)
+		}
+	} else if s.destValueMethod >= 0 {
+		return dest.Method(s.destValueMethod)
+	}
+	return reflect.Value{}
+}
+
+// srcMethod returns the method of source whose result should be copied.
+func (s *methodStep) srcMethod(source reflect.Value) reflect.Value {
+	if source.CanAddr() {
+		if s.srcPtrMethod >= 0 {
+			return source.Addr().Method(s.srcPtrMethod)
+		}
+	} else if s.srcValueMethod >= 0 {
+		return source.Method(s.srcValueMethod)
+	}
+	return reflect.Value{}
+}
+
+// mappingKey returns a string uniquely identifying a field name mapping.
+func mappingKey(mapping map[string]string) string {
+	if len(mapping) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(mapping))
+	for k := range mapping {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(mapping[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+func fieldByNameType(t reflect.Type, name string, caseSensitive bool) (reflect.StructField, bool) {
+	if caseSensitive {
+		return t.FieldByName(name)
+	}
+
+	return t.FieldByNameFunc(func(n string) bool { return strings.EqualFold(n, name) })
+}
@@ -0,0 +1,58 @@
+package copier
+
+import "sync"
+
+// registry holds the converters registered with RegisterConverter.
+var registry struct {
+	mu         sync.RWMutex
+	converters []TypeConverter
+	// indexed converters, nil if there are none
+	set *converterSet
+}
+
+// RegisterConverter registers converters used by every copy, after the converters of its Option.
+// They are matched against each other like the converters of an Option, see TypeConverter.
+// A Copier only uses the converters registered before it was created.
+func RegisterConverter(converters ...TypeConverter) error {
+	if err := validateConverters(converters); err != nil {
+		return err
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.converters = append(registry.converters[:len(registry.converters):len(registry.converters)], converters...)
+	registry.set = newConverterSet(registry.converters, nil)
+	return nil
+}
+
+// Converters returns the registered converters.
+// Along with ResetConverters, it lets tests restore the registry they changed:
+//
+//	defer copier.ResetConverters(copier.Converters()...)
+func Converters() []TypeConverter {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return append([]TypeConverter(nil), registry.converters...)
+}
+
+// ResetConverters replaces the registered converters, removing them all when none are given.
+func ResetConverters(converters ...TypeConverter) error {
+	if err := validateConverters(converters); err != nil {
+		return err
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.converters = append([]TypeConverter(nil), converters...)
+	registry.set = nil
+	if len(converters) > 0 {
+		registry.set = newConverterSet(registry.converters, nil)
+	}
+	return nil
+}
+
+func registeredConverters() *converterSet {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.set
+}
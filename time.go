@@ -0,0 +1,80 @@
+package copier
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TimeUnit is the unit of the Unix epochs converted from and to time.Time values.
+type TimeUnit uint8
+
+const (
+	// UnixSeconds converts time.Time values from and to seconds since the Unix epoch.
+	UnixSeconds TimeUnit = iota
+	// UnixMillis converts time.Time values from and to milliseconds since the Unix epoch.
+	UnixMillis
+)
+
+// TimeConverters returns converters, to add to Option.Converters or to register with RegisterConverter, between:
+//   - time.Time and string, formatted with layout or time.RFC3339 if empty; an empty string is the zero time
+//   - *time.Time and string, a nil pointer being an empty string
+//   - time.Time and int64 Unix epochs in the given unit, converted to UTC times
+//   - time.Time and sql.NullTime, the zero time being an invalid sql.NullTime
+//   - time.Duration and string, formatted like "1h30m"
+func TimeConverters(layout string, unit TimeUnit) []TypeConverter {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	parse := func(s string) (time.Time, error) {
+		if s == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(layout, s)
+	}
+
+	return []TypeConverter{
+		Converter(func(t time.Time) (string, error) {
+			return t.Format(layout), nil
+		}),
+		Converter(parse),
+		Converter(func(t *time.Time) (string, error) {
+			if t == nil {
+				return "", nil
+			}
+			return t.Format(layout), nil
+		}),
+		Converter(func(s string) (*time.Time, error) {
+			if s == "" {
+				return nil, nil
+			}
+			t, err := parse(s)
+			return &t, err
+		}),
+		Converter(func(t time.Time) (int64, error) {
+			if unit == UnixMillis {
+				return t.UnixMilli(), nil
+			}
+			return t.Unix(), nil
+		}),
+		Converter(func(epoch int64) (time.Time, error) {
+			if unit == UnixMillis {
+				return time.UnixMilli(epoch).UTC(), nil
+			}
+			return time.Unix(epoch, 0).UTC(), nil
+		}),
+		Converter(func(t time.Time) (sql.NullTime, error) {
+			return sql.NullTime{Time: t, Valid: !t.IsZero()}, nil
+		}),
+		Converter(func(t sql.NullTime) (time.Time, error) {
+			if !t.Valid {
+				return time.Time{}, nil
+			}
+			return t.Time, nil
+		}),
+		Converter(func(d time.Duration) (string, error) {
+			return d.String(), nil
+		}),
+		Converter(time.ParseDuration),
+	}
+}
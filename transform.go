@@ -0,0 +1,129 @@
+package copier
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldTransformer replaces the value copied into a field of a destination struct.
+type FieldTransformer struct {
+	// DstType is a destination struct type, such as User{}.
+	DstType interface{}
+	// Path of the transformed field from DstType, such as "Address.ZipCode".
+	// Slice indices and map keys are left out, "Friends.Email" transforms the email of every friend.
+	Path string
+	// Fn receives the source field value and returns the value to copy into the destination field.
+	Fn func(src interface{}) (dst interface{}, err error)
+}
+
+type transformerKey struct {
+	dstType reflect.Type
+	path    string
+}
+
+func (opt Option) fieldTransformers() map[transformerKey]FieldTransformer {
+	if len(opt.FieldTransformers) == 0 {
+		return nil
+	}
+
+	transformers := make(map[transformerKey]FieldTransformer, len(opt.FieldTransformers))
+	for _, t := range opt.FieldTransformers {
+		dstType, _ := indirectType(reflect.TypeOf(t.DstType))
+		transformers[transformerKey{dstType: dstType, path: t.Path}] = t
+	}
+	return transformers
+}
+
+// structFrame is a destination struct being copied field by field.
+type structFrame struct {
+	typ reflect.Type
+	// length of the destination path when the struct was entered
+	dst int
+}
+
+// enterStruct records that the fields of a toType struct are being copied, until the path is reset.
+func (s *state) enterStruct(toType reflect.Type) {
+	if len(s.transformers) > 0 {
+		s.structs = append(s.structs, structFrame{typ: toType, dst: len(s.dstPath)})
+	}
+}
+
+// relativePath returns the field names of the destination path from the given frame, joined with dots.
+func (s *state) relativePath(frame structFrame) string {
+	var names []string
+	for _, e := range s.dstPath[frame.dst:] {
+		if e.kind == pathField {
+			names = append(names, e.name)
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// transformsInto reports whether some field inside a toType value at the current path has a transformer,
+// in which case the value must be copied field by field rather than assigned as a whole.
+func (s *state) transformsInto(toType reflect.Type) bool {
+	if len(s.transformers) == 0 {
+		return false
+	}
+	switch toType.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return false
+	}
+
+	elemType, _ := indirectType(toType)
+	for key := range s.transformers {
+		if key.dstType == elemType {
+			return true
+		}
+		for _, frame := range s.structs {
+			if key.dstType == frame.typ && strings.HasPrefix(key.path, s.relativePath(frame)+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transformer returns the transformer of the destination field at the current path, if any.
+// The transformers of the innermost structs come first.
+func (s *state) transformer() (FieldTransformer, bool) {
+	if len(s.transformers) == 0 {
+		return FieldTransformer{}, false
+	}
+
+	for i := len(s.structs) - 1; i >= 0; i-- {
+		frame := s.structs[i]
+		if t, ok := s.transformers[transformerKey{dstType: frame.typ, path: s.relativePath(frame)}]; ok {
+			return t, true
+		}
+	}
+	return FieldTransformer{}, false
+}
+
+// transformValue copies the transformed value of from into to.
+func transformValue(to, from reflect.Value, t FieldTransformer, s *state) error {
+	result, err := t.Fn(from.Interface())
+	if err != nil {
+		return s.wrap(err, from.Type(), to.Type())
+	}
+
+	if result == nil {
+		to.Set(reflect.Zero(to.Type()))
+		return nil
+	}
+
+	// results of the destination type are set as is, without going through converters
+	v := reflect.ValueOf(result)
+	switch {
+	case v.Type().AssignableTo(to.Type()):
+		to.Set(v)
+	case to.Kind() == reflect.Ptr && v.Type().AssignableTo(to.Type().Elem()):
+		ptr := reflect.New(to.Type().Elem())
+		ptr.Elem().Set(v)
+		to.Set(ptr)
+	default:
+		return copyValue(to, v, s)
+	}
+	return nil
+}
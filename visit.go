@@ -0,0 +1,76 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// visitKey identifies a source pointer copied into a destination pointer type.
+type visitKey struct {
+	addr     uintptr
+	from, to reflect.Type
+}
+
+// visit is the destination pointer a source pointer has been deep copied into.
+type visit struct {
+	to reflect.Value
+	// number of copies of the pointed value in progress, more than zero while copying a cycle
+	active int
+}
+
+func newVisitKey(from, to reflect.Value) visitKey {
+	return visitKey{addr: from.Pointer(), from: from.Type(), to: to.Type()}
+}
+
+// lookupVisited sets `to` to the destination pointer `from` has already been copied into, if any,
+// so that shared pointers and cycles of the source are reproduced in the destination.
+func lookupVisited(to, from reflect.Value, s *state) (bool, error) {
+	v, ok := s.visited[newVisitKey(from, to)]
+	if !ok {
+		return false, nil
+	}
+
+	if v.active > 0 && s.ErrorOnCycle {
+		return false, fmt.Errorf("%w: %v at %#x", ErrCycleDetected, from.Type(), from.Pointer())
+	}
+	to.Set(v.to)
+	return true, nil
+}
+
+// rememberVisited records that `from` is being deep copied into `to`.
+func rememberVisited(to, from reflect.Value, s *state) {
+	if s.visited == nil {
+		s.visited = map[visitKey]*visit{}
+	}
+	s.visited[newVisitKey(from, to)] = &visit{to: to}
+}
+
+// enterVisited marks the value pointed by fromValue as being copied into the one pointed by toValue.
+// The copy must be skipped if the value is already being copied higher in a cycle.
+func enterVisited(toValue, fromValue interface{}, s *state) (v *visit, skip bool, err error) {
+	from, to := reflect.ValueOf(fromValue), reflect.ValueOf(toValue)
+	if from.Kind() != reflect.Ptr || from.IsNil() || to.Kind() != reflect.Ptr || to.IsNil() {
+		return nil, false, nil
+	}
+	for from.Elem().Kind() == reflect.Ptr && !from.Elem().IsNil() {
+		from = from.Elem()
+	}
+	for to.Elem().Kind() == reflect.Ptr && !to.Elem().IsNil() {
+		to = to.Elem()
+	}
+
+	key := newVisitKey(from, to)
+	v, ok := s.visited[key]
+	if !ok {
+		rememberVisited(to, from, s)
+		v = s.visited[key]
+	} else if v.active > 0 {
+		if s.ErrorOnCycle {
+			return nil, false, fmt.Errorf("%w: %v at %#x", ErrCycleDetected, from.Type(), from.Pointer())
+		}
+		return nil, true, nil
+	}
+
+	v.active++
+	return v, false, nil
+}